@@ -0,0 +1,318 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/config"
+	"pokefactory_server/internal/database"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Manage Minecraft server credentials.
+
+Usage:
+  gameserver add <server_id> [scope...]
+                                   Register a server and print its new key
+                                   (default scopes: player:read player:write
+                                   pokedex:read pokedex:write)
+  gameserver scopes <server_id> <scope...>
+                                   Replace the scopes granted to a server
+  gameserver rotate <server_id>    Replace a server's key and signing secret and
+                                   print the new ones
+  gameserver secret <server_id>    Replace only a server's signing secret and
+                                   print the new one
+  gameserver signing <server_id> required|optional
+                                   Require HMAC-signed requests from a server
+  gameserver enable <server_id>    Allow a server to authenticate
+  gameserver disable <server_id>   Block a server and revoke its active tokens
+  gameserver revoke <server_id>    Revoke a server's active tokens (it can re-authenticate)
+  gameserver remove <server_id>    Delete a server's credentials
+  gameserver list                  List registered servers`
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	if err := database.RunMigrations(cfg.Database); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	command := os.Args[1]
+	if command == "list" {
+		if err := listServers(db); err != nil {
+			log.Fatal("Failed to list servers:", err)
+		}
+		return
+	}
+
+	if len(os.Args) < 3 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	serverID := os.Args[2]
+
+	if command == "signing" {
+		if len(os.Args) != 4 || (os.Args[3] != "required" && os.Args[3] != "optional") {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		if err := setServerSigning(db, serverID, os.Args[3] == "required"); err != nil {
+			log.Fatalf("Failed to update signing for server %s: %v", serverID, err)
+		}
+		return
+	}
+
+	scopes := os.Args[3:]
+	for _, scope := range scopes {
+		if !auth.IsKnownScope(scope) {
+			log.Fatalf("Unknown scope %q", scope)
+		}
+	}
+
+	switch command {
+	case "add":
+		if len(scopes) == 0 {
+			scopes = auth.DefaultScopes
+		}
+		err = addServer(db, cfg.Signing.SecretKey, serverID, scopes)
+	case "scopes":
+		if len(scopes) == 0 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		err = setServerScopes(db, serverID, scopes)
+	case "rotate":
+		err = rotateServerKey(db, cfg.Signing.SecretKey, serverID)
+	case "secret":
+		err = rotateSigningSecret(db, cfg.Signing.SecretKey, serverID)
+	case "enable":
+		err = setServerEnabled(db, serverID, true)
+	case "disable":
+		if err = setServerEnabled(db, serverID, false); err == nil {
+			err = revokeServerSessions(db, serverID)
+		}
+	case "revoke":
+		err = revokeServerSessions(db, serverID)
+	case "remove":
+		if err = revokeServerSessions(db, serverID); err == nil {
+			err = removeServer(db, serverID)
+		}
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to %s server %s: %v", command, serverID, err)
+	}
+}
+
+func addServer(db *sql.DB, secretKey, serverID string, scopes []string) error {
+	key, err := auth.GenerateServerKey()
+	if err != nil {
+		return err
+	}
+	secret, sealed, err := issueSigningSecret(secretKey, serverID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO game_servers (server_id, key_hash, signing_secret_encrypted, enabled, scopes, created_at, updated_at)
+		VALUES ($1, $2, $3, TRUE, $4, NOW(), NOW())`
+	if _, err := db.Exec(query, serverID, auth.HashServerKey(key), sealed, auth.FormatScopes(scopes)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered server %s with scopes: %s\nServer key: %s\n", serverID, auth.FormatScopes(scopes), key)
+	printSigningSecret(secret)
+	fmt.Println("Store these in the mod config now - they cannot be shown again.")
+	return nil
+}
+
+func rotateServerKey(db *sql.DB, secretKey, serverID string) error {
+	key, err := auth.GenerateServerKey()
+	if err != nil {
+		return err
+	}
+	secret, sealed, err := issueSigningSecret(secretKey, serverID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE game_servers SET key_hash = $1, signing_secret_encrypted = $2, updated_at = NOW() WHERE server_id = $3`
+	if err := execOne(db, query, auth.HashServerKey(key), sealed, serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Rotated key for server %s\nServer key: %s\n", serverID, key)
+	printSigningSecret(secret)
+	return nil
+}
+
+// rotateSigningSecret issues a new signing secret without touching the
+// server key, e.g. for servers registered before secrets existed.
+func rotateSigningSecret(db *sql.DB, secretKey, serverID string) error {
+	if secretKey == "" {
+		return auth.ErrSigningSecretKey
+	}
+	secret, sealed, err := issueSigningSecret(secretKey, serverID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE game_servers SET signing_secret_encrypted = $1, updated_at = NOW() WHERE server_id = $2`
+	if err := execOne(db, query, sealed, serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Rotated signing secret for server %s\n", serverID)
+	printSigningSecret(secret)
+	return nil
+}
+
+// issueSigningSecret generates a signing secret and seals it for storage.
+// Without REQUEST_SIGNING_SECRET_KEY no secret is issued and the sealed value
+// is NULL, leaving the server unable to sign requests.
+func issueSigningSecret(secretKey, serverID string) (string, sql.NullString, error) {
+	if secretKey == "" {
+		return "", sql.NullString{}, nil
+	}
+
+	secret, err := auth.GenerateSigningSecret()
+	if err != nil {
+		return "", sql.NullString{}, err
+	}
+	sealed, err := auth.SealSigningSecret(secretKey, serverID, secret)
+	if err != nil {
+		return "", sql.NullString{}, err
+	}
+	return secret, sql.NullString{String: sealed, Valid: true}, nil
+}
+
+func printSigningSecret(secret string) {
+	if secret == "" {
+		fmt.Println("No signing secret issued: set REQUEST_SIGNING_SECRET_KEY to enable request signing.")
+		return
+	}
+	fmt.Printf("Signing secret: %s\n", secret)
+}
+
+func setServerEnabled(db *sql.DB, serverID string, enabled bool) error {
+	query := `UPDATE game_servers SET enabled = $1, updated_at = NOW() WHERE server_id = $2`
+	if err := execOne(db, query, enabled, serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Server %s enabled: %t\n", serverID, enabled)
+	return nil
+}
+
+// setServerScopes changes what a server may do. Tokens already issued keep
+// their old scopes until they are refreshed, so revoke them to apply at once.
+func setServerScopes(db *sql.DB, serverID string, scopes []string) error {
+	query := `UPDATE game_servers SET scopes = $1, updated_at = NOW() WHERE server_id = $2`
+	if err := execOne(db, query, auth.FormatScopes(scopes), serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Server %s scopes: %s\n", serverID, auth.FormatScopes(scopes))
+	return nil
+}
+
+func setServerSigning(db *sql.DB, serverID string, required bool) error {
+	query := `UPDATE game_servers SET require_signing = $1, updated_at = NOW() WHERE server_id = $2`
+	if err := execOne(db, query, required, serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Server %s requires signed requests: %t\n", serverID, required)
+	return nil
+}
+
+func revokeServerSessions(db *sql.DB, serverID string) error {
+	query := `UPDATE auth_sessions SET revoked_at = NOW() WHERE server_id = $1 AND revoked_at IS NULL`
+	result, err := db.Exec(query, serverID)
+	if err != nil {
+		return err
+	}
+
+	revoked, _ := result.RowsAffected()
+	fmt.Printf("Revoked %d active session(s) for server %s\n", revoked, serverID)
+	return nil
+}
+
+func removeServer(db *sql.DB, serverID string) error {
+	if err := execOne(db, `DELETE FROM game_servers WHERE server_id = $1`, serverID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed server %s\n", serverID)
+	return nil
+}
+
+func listServers(db *sql.DB) error {
+	rows, err := db.Query(`SELECT server_id, enabled, require_signing, scopes, last_auth_at FROM game_servers ORDER BY server_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fmt.Printf("%-32s %-8s %-8s %-26s %s\n", "SERVER_ID", "ENABLED", "SIGNING", "LAST_AUTH", "SCOPES")
+	for rows.Next() {
+		var serverID, scopes string
+		var enabled, requireSigning bool
+		var lastAuth *time.Time
+		if err := rows.Scan(&serverID, &enabled, &requireSigning, &scopes, &lastAuth); err != nil {
+			return err
+		}
+
+		lastAuthText := "never"
+		if lastAuth != nil {
+			lastAuthText = lastAuth.Format(time.RFC3339)
+		}
+		signing := "optional"
+		if requireSigning {
+			signing = "required"
+		}
+		fmt.Printf("%-32s %-8t %-8s %-26s %s\n", serverID, enabled, signing, lastAuthText, scopes)
+	}
+
+	return rows.Err()
+}
+
+func execOne(db *sql.DB, query string, args ...interface{}) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"log"
-	"os"
 
 	"pokefactory_server/internal/api"
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/catalog"
 	"pokefactory_server/internal/config"
 	"pokefactory_server/internal/database"
+	"pokefactory_server/internal/ratelimit"
 
 	"github.com/joho/godotenv"
 )
@@ -32,17 +34,48 @@ func main() {
 		log.Fatal("Failed to run migrations:", err)
 	}
 
+	// Load token signing keys
+	keys, err := auth.LoadKeySet(cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load JWT keys:", err)
+	}
+
+	// Set up rate limiting
+	limiter, err := ratelimit.New(cfg.RateLimit, db)
+	if err != nil {
+		log.Fatal("Failed to configure rate limiting:", err)
+	}
+
+	// Load species catalog
+	dex, err := catalog.Load()
+	if err != nil {
+		log.Fatal("Failed to load species catalog:", err)
+	}
+	log.Printf("Loaded species catalog %s (%d species, %d regions)", dex.Version(), dex.Total(), len(dex.Regions()))
+
 	// Initialize API server
-	server := api.NewServer(db, cfg)
+	server := api.NewServer(db, cfg, keys, limiter, dex)
 
-	// Start server
-	port := os.Getenv("API_PORT")
-	if port == "" {
-		port = "8080"
+	// Bring stored Pokédex completion up to date with a changed catalog
+	recomputed, err := server.ApplyCatalogVersion()
+	if err != nil {
+		log.Fatal("Failed to apply species catalog:", err)
+	}
+	if recomputed > 0 {
+		log.Printf("Recomputed Pokédex completion for %d players for catalog %s", recomputed, dex.Version())
+	}
+
+	// Start listeners
+	if cfg.TLS.CertFile != "" {
+		log.Printf("Starting API on %s:%s (TLS, client certificates: %s)", cfg.Server.Host, cfg.Server.Port, cfg.TLS.ClientAuth)
+	} else {
+		log.Printf("Starting API on %s:%s", cfg.Server.Host, cfg.Server.Port)
+	}
+	if cfg.Server.WebPort != "" {
+		log.Printf("Starting web dashboard on %s:%s", cfg.Server.WebHost, cfg.Server.WebPort)
 	}
 
-	log.Printf("Starting server on port %s", port)
-	if err := server.Run(":" + port); err != nil {
+	if err := server.Run(); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
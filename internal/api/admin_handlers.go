@@ -0,0 +1,270 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Admin endpoints for moderators. All routes require the admin scope.
+func (s *Server) adminSearchPlayers(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	players, err := s.searchPlayers(c.Query("q"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search players"})
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
+
+func (s *Server) adminGetPlayer(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	detail := models.AdminPlayerDetail{Player: *player}
+	if stats, err := s.getPlayerStatsByID(player.ID); err == nil {
+		detail.Stats = stats
+	}
+	if summary, err := s.getPokedexSummaryByID(s.db, player.ID); err == nil {
+		detail.Pokedex = summary
+	}
+	detail.Data, _ = s.getAllPlayerData(player.ID)
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func (s *Server) adminUpdatePlayerStats(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	var stats models.PlayerStats
+	if err := c.ShouldBindJSON(&stats); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats.PlayerID = player.ID
+	if err := s.auditedUpdatePlayerStats(c, stats); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stats updated successfully"})
+}
+
+func (s *Server) adminRenamePlayer(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req models.AdminRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.auditedUpdatePlayer(c, player.ID, req.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Player renamed successfully"})
+}
+
+func (s *Server) adminSetPlayerRole(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req models.AdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !auth.IsKnownRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	changed, err := s.updatePlayerRole(player.ID, req.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+	if !changed {
+		c.JSON(http.StatusOK, gin.H{"message": "Role unchanged"})
+		return
+	}
+	s.recordAudit(c, player.ID, "player.role.update", gin.H{"role": player.Role}, gin.H{"role": req.Role})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully"})
+}
+
+func (s *Server) adminUpdatePokedex(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req models.AdminPokedexUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateReq := models.PokedexUpdateRequest{
+		NationalID: req.NationalID,
+		Action:     req.Action,
+		Form:       req.Form,
+		Shiny:      req.Shiny,
+	}
+
+	if err := s.auditedUpdatePokedexEntry(c, player.ID, updateReq); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Pokédex"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pokédex updated successfully"})
+}
+
+func (s *Server) adminResetPokedex(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req models.AdminPokedexResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Region != "" {
+		if _, exists := s.catalog.Region(req.Region); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+	}
+
+	if err := s.auditedResetPokedex(c, player.ID, req.Region); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset Pokédex"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pokédex reset successfully"})
+}
+
+func (s *Server) adminRecomputePokedex(c *gin.Context) {
+	player, ok := s.adminLoadPlayer(c)
+	if !ok {
+		return
+	}
+
+	err := s.withPokedexLock(player.ID, func(tx *pokedexTx) error {
+		return s.recomputePokedex(tx, player.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute Pokédex"})
+		return
+	}
+
+	summary, err := s.getPokedexSummaryByID(s.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pokédex summary not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// adminGetAuditLog lists audit entries, newest first. Filters: player_id,
+// actor_type, actor_id and an RFC 3339 since/until time range.
+func (s *Server) adminGetAuditLog(c *gin.Context) {
+	filter := models.AuditLogFilter{
+		ActorType: c.Query("actor_type"),
+		ActorID:   c.Query("actor_id"),
+	}
+
+	if value := c.Query("player_id"); value != "" {
+		playerID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+			return
+		}
+		filter.PlayerID = playerID
+	}
+
+	for _, bound := range []struct {
+		param string
+		dest  **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + bound.param + " time, expected RFC 3339"})
+			return
+		}
+		*bound.dest = &t
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	entries, err := s.getAuditLog(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// adminLoadPlayer resolves the :id route parameter, writing the error response
+// itself when the ID is invalid or unknown.
+func (s *Server) adminLoadPlayer(c *gin.Context) (*models.Player, bool) {
+	playerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return nil, false
+	}
+
+	player, err := s.getPlayerByID(playerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return nil, false
+	}
+
+	return player, true
+}
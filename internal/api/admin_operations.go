@@ -0,0 +1,87 @@
+package api
+
+import (
+	"strings"
+
+	"pokefactory_server/internal/models"
+)
+
+// likeEscaper escapes LIKE wildcards so a search term matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s *Server) searchPlayers(search string, limit, offset int) ([]models.Player, error) {
+	query := `
+		SELECT id, uuid, username, role, privacy, last_login, created_at, updated_at
+		FROM players
+		WHERE $1 = '' OR uuid = $1 OR username ILIKE '%' || $4 || '%' ESCAPE '\'
+		ORDER BY LOWER(username), id
+		LIMIT $2 OFFSET $3`
+
+	rows, err := s.db.Query(query, search, limit, offset, likeEscaper.Replace(search))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := []models.Player{}
+	for rows.Next() {
+		var player models.Player
+		if err := rows.Scan(&player.ID, &player.UUID, &player.Username, &player.Role, &player.Privacy,
+			&player.LastLogin, &player.CreatedAt, &player.UpdatedAt); err != nil {
+			continue
+		}
+		players = append(players, player)
+	}
+
+	return players, nil
+}
+
+func (s *Server) getAllPlayerData(playerID int) ([]models.PlayerData, error) {
+	query := `SELECT id, player_id, data_key, data_value, created_at, updated_at FROM player_data WHERE player_id = $1 ORDER BY data_key`
+
+	rows, err := s.db.Query(query, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dataList := []models.PlayerData{}
+	for rows.Next() {
+		var data models.PlayerData
+		if err := rows.Scan(&data.ID, &data.PlayerID, &data.DataKey, &data.DataValue,
+			&data.CreatedAt, &data.UpdatedAt); err != nil {
+			continue
+		}
+		dataList = append(dataList, data)
+	}
+
+	return dataList, nil
+}
+
+// updatePlayerRole changes a player's role and revokes their game sessions,
+// whose scopes were granted for the old role, so the change takes effect
+// immediately rather than at the next login. It reports whether the role
+// changed; setting the current role again changes nothing.
+func (s *Server) updatePlayerRole(playerID int, role string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE players SET role = $1, updated_at = NOW() WHERE id = $2 AND role IS DISTINCT FROM $1`
+	result, err := tx.Exec(query, role, playerID)
+	if err != nil {
+		return false, err
+	}
+	if changed, err := result.RowsAffected(); err != nil || changed == 0 {
+		return false, err
+	}
+
+	query = `UPDATE auth_sessions SET revoked_at = NOW() WHERE player_id = $1 AND session_type = $2 AND revoked_at IS NULL`
+	if _, err := tx.Exec(query, playerID, sessionTypePlayer); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"pokefactory_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditActorServer = "server"
+	auditActorPlayer = "player"
+)
+
+// auditActor identifies who is making the request: the game server for
+// server tokens, otherwise the authenticated player.
+func auditActor(c *gin.Context) (string, string) {
+	if serverID := c.GetString("server_id"); serverID != "" {
+		return auditActorServer, serverID
+	}
+	return auditActorPlayer, strconv.Itoa(int(c.GetFloat64("player_id")))
+}
+
+// recordAudit writes an audit_log entry for a mutation of playerID's data.
+// Failures are logged rather than returned so that auditing never undoes a
+// write that has already been committed.
+func (s *Server) recordAudit(c *gin.Context, playerID int, action string, before, after interface{}) {
+	actorType, actorID := auditActor(c)
+
+	route := c.Request.Method + " " + c.FullPath()
+
+	beforeJSON, err := auditValue(before)
+	if err != nil {
+		log.Printf("audit: failed to encode before value for %s: %v", action, err)
+	}
+	afterJSON, err := auditValue(after)
+	if err != nil {
+		log.Printf("audit: failed to encode after value for %s: %v", action, err)
+	}
+
+	query := `
+		INSERT INTO audit_log (actor_type, actor_id, player_id, action, route, before_value, after_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	if _, err := s.db.Exec(query, actorType, actorID, playerID, action, route, beforeJSON, afterJSON); err != nil {
+		log.Printf("audit: failed to record %s for player %d: %v", action, playerID, err)
+	}
+}
+
+// auditValue encodes a before/after value, mapping missing values to NULL.
+func auditValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *Server) getAuditLog(filter models.AuditLogFilter) ([]models.AuditLogEntry, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.PlayerID > 0 {
+		addCondition("player_id = $%d", filter.PlayerID)
+	}
+	if filter.ActorType != "" {
+		addCondition("actor_type = $%d", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Since != nil {
+		addCondition("created_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		addCondition("created_at < $%d", *filter.Until)
+	}
+
+	query := `SELECT id, actor_type, actor_id, player_id, action, route, before_value, after_value, created_at FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLogEntry{}
+	for rows.Next() {
+		var entry models.AuditLogEntry
+		var before, after []byte
+		if err := rows.Scan(&entry.ID, &entry.ActorType, &entry.ActorID, &entry.PlayerID,
+			&entry.Action, &entry.Route, &before, &after, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.Before = json.RawMessage(before)
+		entry.After = json.RawMessage(after)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Audited mutations shared by the player, server and admin handlers. Each
+// captures the affected value before and after the write.
+
+// auditedGetOrCreatePlayer only records an entry when the player is created
+// or renamed; a plain login refresh is not worth auditing.
+func (s *Server) auditedGetOrCreatePlayer(c *gin.Context, uuid, username string) (*models.Player, error) {
+	var before *models.Player
+	if existing, err := s.getPlayerByUUID(uuid); err == nil {
+		before = existing
+	}
+
+	player, err := s.getOrCreatePlayer(uuid, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		s.recordAudit(c, player.ID, "player.create", nil, player)
+	} else if before.Username != username {
+		after, _ := s.getPlayerByID(player.ID)
+		s.recordAudit(c, player.ID, "player.update", before, after)
+	}
+	return player, nil
+}
+
+func (s *Server) auditedUpdatePlayer(c *gin.Context, playerID int, username string) error {
+	before, _ := s.getPlayerByID(playerID)
+
+	if err := s.updatePlayer(playerID, username); err != nil {
+		return err
+	}
+
+	after, _ := s.getPlayerByID(playerID)
+	s.recordAudit(c, playerID, "player.update", before, after)
+	return nil
+}
+
+func (s *Server) auditedUpdatePlayerPrivacy(c *gin.Context, playerID int, privacy string) error {
+	before, _ := s.getPlayerByID(playerID)
+
+	if err := s.updatePlayerPrivacy(playerID, privacy); err != nil {
+		return err
+	}
+
+	after, _ := s.getPlayerByID(playerID)
+	s.recordAudit(c, playerID, "player.privacy.update", before, after)
+	return nil
+}
+
+func (s *Server) auditedUpdatePlayerStats(c *gin.Context, stats models.PlayerStats) error {
+	before, _ := s.getPlayerStatsByID(stats.PlayerID)
+
+	if err := s.updatePlayerStatsByID(stats); err != nil {
+		return err
+	}
+
+	after, _ := s.getPlayerStatsByID(stats.PlayerID)
+	s.recordAudit(c, stats.PlayerID, "player.stats.update", before, after)
+	return nil
+}
+
+func (s *Server) auditedSetPlayerData(c *gin.Context, playerID int, key, value string) error {
+	var before *models.PlayerData
+	if data, err := s.getPlayerDataByKey(playerID, key); err == nil {
+		before = data
+	}
+
+	if err := s.setPlayerDataByKey(playerID, key, value); err != nil {
+		return err
+	}
+
+	after, _ := s.getPlayerDataByKey(playerID, key)
+	s.recordAudit(c, playerID, "player.data.set", before, after)
+	return nil
+}
+
+// auditedUpdatePokedexEntry reads the before and after state inside the same
+// locked transaction as the write, so they describe exactly this update.
+func (s *Server) auditedUpdatePokedexEntry(c *gin.Context, playerID int, req models.PokedexUpdateRequest) error {
+	var before, after *models.PokedexEntryState
+	err := s.withPokedexLock(playerID, func(tx *pokedexTx) error {
+		before, _ = s.getPokedexEntryState(tx, playerID, req)
+
+		if err := s.updatePokedexEntry(tx, playerID, req); err != nil {
+			return err
+		}
+
+		after, _ = s.getPokedexEntryState(tx, playerID, req)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordAudit(c, playerID, "pokedex."+req.Action, before, after)
+	return nil
+}
+
+// auditedSetPokedexOwnership stores an ownership report and recomputes the
+// summary, auditing the living dex figures before and after.
+func (s *Server) auditedSetPokedexOwnership(c *gin.Context, playerID int, counts []models.PokedexOwnership, partial bool) error {
+	var before, after *models.PokedexSummary
+	err := s.withPokedexLock(playerID, func(tx *pokedexTx) error {
+		before, _ = s.getPokedexSummaryByID(tx, playerID)
+
+		if err := s.setPokedexOwnership(tx, playerID, counts, partial); err != nil {
+			return err
+		}
+		if err := s.updatePokedexCompletion(tx, playerID); err != nil {
+			return err
+		}
+
+		after, _ = s.getPokedexSummaryByID(tx, playerID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordAudit(c, playerID, "pokedex.ownership", before, after)
+	return nil
+}
+
+// auditedResetPokedex clears one region, or with an empty region every region
+// plus the form entries and journal, then recomputes completion.
+func (s *Server) auditedResetPokedex(c *gin.Context, playerID int, region string) error {
+	regions := []string{region}
+	if region == "" {
+		regions = nil
+		for _, r := range s.catalog.Regions() {
+			regions = append(regions, r.Name)
+		}
+	}
+
+	var before, after *models.PokedexSummary
+	err := s.withPokedexLock(playerID, func(tx *pokedexTx) error {
+		before, _ = s.getPokedexSummaryByID(tx, playerID)
+
+		for _, r := range regions {
+			if err := s.resetRegionalPokedex(tx, playerID, r); err != nil {
+				return err
+			}
+		}
+		if region == "" {
+			if err := s.resetPokedexForms(tx, playerID); err != nil {
+				return err
+			}
+			if err := s.resetPokedexEntries(tx, playerID); err != nil {
+				return err
+			}
+		}
+		if err := s.recomputePokedex(tx, playerID); err != nil {
+			return err
+		}
+
+		after, _ = s.getPokedexSummaryByID(tx, playerID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordAudit(c, playerID, "pokedex.reset", before, after)
+	return nil
+}
@@ -18,11 +18,11 @@ func (s *Server) getOrCreatePlayer(uuid, username string) (*models.Player, error
 	query := `
 		INSERT INTO players (uuid, username, last_login, created_at, updated_at)
 		VALUES ($1, $2, NOW(), NOW(), NOW())
-		RETURNING id, uuid, username, last_login, created_at, updated_at`
+		RETURNING id, uuid, username, role, privacy, last_login, created_at, updated_at`
 
 	player = &models.Player{}
 	err = s.db.QueryRow(query, uuid, username).Scan(
-		&player.ID, &player.UUID, &player.Username,
+		&player.ID, &player.UUID, &player.Username, &player.Role, &player.Privacy,
 		&player.LastLogin, &player.CreatedAt, &player.UpdatedAt,
 	)
 	if err != nil {
@@ -31,17 +31,29 @@ func (s *Server) getOrCreatePlayer(uuid, username string) (*models.Player, error
 
 	// Create initial stats and pokedex
 	s.createPlayerStats(player.ID)
-	s.getOrCreatePokedexSummary(player.ID)
+	s.getOrCreatePokedexSummary(s.db, player.ID)
 
 	return player, nil
 }
 
 func (s *Server) getPlayerByUUID(uuid string) (*models.Player, error) {
-	query := `SELECT id, uuid, username, last_login, created_at, updated_at FROM players WHERE uuid = $1`
+	query := `SELECT id, uuid, username, role, privacy, last_login, created_at, updated_at FROM players WHERE uuid = $1`
 	
 	player := &models.Player{}
 	err := s.db.QueryRow(query, uuid).Scan(
-		&player.ID, &player.UUID, &player.Username,
+		&player.ID, &player.UUID, &player.Username, &player.Role, &player.Privacy,
+		&player.LastLogin, &player.CreatedAt, &player.UpdatedAt,
+	)
+	
+	return player, err
+}
+
+func (s *Server) getPlayerByID(playerID int) (*models.Player, error) {
+	query := `SELECT id, uuid, username, role, privacy, last_login, created_at, updated_at FROM players WHERE id = $1`
+	
+	player := &models.Player{}
+	err := s.db.QueryRow(query, playerID).Scan(
+		&player.ID, &player.UUID, &player.Username, &player.Role, &player.Privacy,
 		&player.LastLogin, &player.CreatedAt, &player.UpdatedAt,
 	)
 	
@@ -49,11 +61,11 @@ func (s *Server) getPlayerByUUID(uuid string) (*models.Player, error) {
 }
 
 func (s *Server) getPlayerByUsername(username string) (*models.Player, error) {
-	query := `SELECT id, uuid, username, last_login, created_at, updated_at FROM players WHERE username = $1`
+	query := `SELECT id, uuid, username, role, privacy, last_login, created_at, updated_at FROM players WHERE username = $1`
 	
 	player := &models.Player{}
 	err := s.db.QueryRow(query, username).Scan(
-		&player.ID, &player.UUID, &player.Username,
+		&player.ID, &player.UUID, &player.Username, &player.Role, &player.Privacy,
 		&player.LastLogin, &player.CreatedAt, &player.UpdatedAt,
 	)
 	
@@ -72,6 +84,12 @@ func (s *Server) updatePlayer(playerID int, username string) error {
 	return err
 }
 
+func (s *Server) updatePlayerPrivacy(playerID int, privacy string) error {
+	query := `UPDATE players SET privacy = $1, updated_at = NOW() WHERE id = $2`
+	_, err := s.db.Exec(query, privacy, playerID)
+	return err
+}
+
 func (s *Server) createPlayerStats(playerID int) error {
 	query := `
 		INSERT INTO player_stats (player_id, level, experience, currency, play_time, created_at, updated_at)
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"log"
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/models"
+)
+
+func (s *Server) getGameServerByServerID(serverID string) (*models.GameServer, error) {
+	query := `SELECT id, server_id, key_hash, enabled, scopes, require_signing, signing_secret_encrypted, last_auth_at, created_at, updated_at FROM game_servers WHERE server_id = $1`
+
+	gameServer := &models.GameServer{}
+	err := s.db.QueryRow(query, serverID).Scan(
+		&gameServer.ID, &gameServer.ServerID, &gameServer.KeyHash, &gameServer.Enabled,
+		&gameServer.Scopes, &gameServer.RequireSigning, &gameServer.SigningSecret, &gameServer.LastAuthAt, &gameServer.CreatedAt, &gameServer.UpdatedAt,
+	)
+
+	return gameServer, err
+}
+
+func (s *Server) updateGameServerLastAuth(serverID string) error {
+	query := `UPDATE game_servers SET last_auth_at = NOW() WHERE server_id = $1`
+	_, err := s.db.Exec(query, serverID)
+	return err
+}
+
+// serverSigningPolicy supplies the request signing key and requirement for
+// middleware.RequestSignature. Servers sign with their own signing secret,
+// which is stored sealed with REQUEST_SIGNING_SECRET_KEY; a server that has
+// not been issued one gets a nil key and cannot send signed requests.
+func (s *Server) serverSigningPolicy(serverID string) ([]byte, bool, error) {
+	gameServer, err := s.getGameServerByServerID(serverID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	required := s.config.Signing.Required || gameServer.RequireSigning
+	if gameServer.SigningSecret == nil {
+		return nil, required, nil
+	}
+
+	key, err := auth.OpenSigningSecret(s.config.Signing.SecretKey, serverID, *gameServer.SigningSecret)
+	if err != nil {
+		log.Printf("Failed to open signing secret for server %s: %v", serverID, err)
+		return nil, false, err
+	}
+	return key, required, nil
+}
+
+// recordRequestNonce stores a signed request's nonce, reporting false when
+// the server has already used it.
+func (s *Server) recordRequestNonce(serverID, nonce string, expiresAt time.Time) (bool, error) {
+	// Clear out nonces whose timestamps would be rejected anyway
+	s.db.Exec(`DELETE FROM request_nonces WHERE server_id = $1 AND expires_at < NOW()`, serverID)
+
+	query := `
+		INSERT INTO request_nonces (server_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (server_id, nonce) DO NOTHING`
+	return s.execAffected(query, serverID, nonce, expiresAt)
+}
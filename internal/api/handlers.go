@@ -2,12 +2,12 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"pokefactory_server/internal/models"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 func (s *Server) healthCheck(c *gin.Context) {
@@ -17,10 +17,15 @@ func (s *Server) healthCheck(c *gin.Context) {
 	})
 }
 
+func (s *Server) getJWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, s.keys.JWKS())
+}
+
+// login exchanges a one-time ticket issued by an authenticated Minecraft
+// server (see serverIssueLoginTicket) for a player token.
 func (s *Server) login(c *gin.Context) {
 	var loginReq struct {
-		UUID     string `json:"uuid" binding:"required"`
-		Username string `json:"username" binding:"required"`
+		Ticket string `json:"ticket" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
@@ -28,30 +33,37 @@ func (s *Server) login(c *gin.Context) {
 		return
 	}
 
-	// Get or create player
-	player, err := s.getOrCreatePlayer(loginReq.UUID, loginReq.Username)
+	playerID, serverID, err := s.consumeLoginTicket(loginReq.Ticket)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired login ticket"})
+		return
+	}
+
+	gameServer, err := s.getGameServerByServerID(serverID)
+	if err != nil || !gameServer.Enabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Issuing server is no longer authorized"})
+		return
+	}
+
+	player, err := s.getPlayerByID(playerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate player"})
 		return
 	}
+	s.updatePlayerLogin(player.ID, player.Username)
 
 	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"uuid":      player.UUID,
-		"player_id": player.ID,
-		"username":  player.Username,
-		"exp":       time.Now().Add(time.Hour * 24).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	tokenString, refreshToken, err := s.issuePlayerTokens(player, gameServer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token":  tokenString,
-		"player": player,
+		"token":         tokenString,
+		"refresh_token": refreshToken,
+		"expires_in":    int(playerTokenTTL.Seconds()),
+		"player":        player,
 	})
 }
 
@@ -79,7 +91,7 @@ func (s *Server) updatePlayerProfile(c *gin.Context) {
 		return
 	}
 
-	if err := s.updatePlayer(int(playerID), updateReq.Username); err != nil {
+	if err := s.auditedUpdatePlayer(c, int(playerID), updateReq.Username); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player"})
 		return
 	}
@@ -87,6 +99,41 @@ func (s *Server) updatePlayerProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Player updated successfully"})
 }
 
+func (s *Server) getPlayerPrivacy(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	player, err := s.getPlayerByID(int(playerID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"privacy": player.Privacy})
+}
+
+// updatePlayerPrivacySetting is shared by the player API and the web account
+// pages.
+func (s *Server) updatePlayerPrivacySetting(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	var req models.PrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !models.IsKnownPrivacy(req.Privacy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid privacy setting"})
+		return
+	}
+
+	if err := s.auditedUpdatePlayerPrivacy(c, int(playerID), req.Privacy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update privacy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Privacy updated successfully"})
+}
+
 func (s *Server) getPlayerStats(c *gin.Context) {
 	playerID := c.GetFloat64("player_id")
 	
@@ -109,7 +156,7 @@ func (s *Server) updatePlayerStats(c *gin.Context) {
 	}
 
 	stats.PlayerID = int(playerID)
-	if err := s.updatePlayerStatsByID(stats); err != nil {
+	if err := s.auditedUpdatePlayerStats(c, stats); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stats"})
 		return
 	}
@@ -143,7 +190,7 @@ func (s *Server) setPlayerData(c *gin.Context) {
 		return
 	}
 
-	if err := s.setPlayerDataByKey(int(playerID), key, dataReq.Value); err != nil {
+	if err := s.auditedSetPlayerData(c, int(playerID), key, dataReq.Value); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set data"})
 		return
 	}
@@ -154,7 +201,7 @@ func (s *Server) setPlayerData(c *gin.Context) {
 func (s *Server) getPokedexSummary(c *gin.Context) {
 	playerID := c.GetFloat64("player_id")
 	
-	summary, err := s.getPokedexSummaryByID(int(playerID))
+	summary, err := s.getPokedexSummaryByID(s.db, int(playerID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Pokédex summary not found"})
 		return
@@ -175,13 +222,83 @@ func (s *Server) getRegionalPokedex(c *gin.Context) {
 		return
 	}
 
-	pokedex, err := s.getRegionalPokedexByID(int(playerID), regionReq.Region)
+	pokedex, err := s.getRegionalPokedexByID(s.db, int(playerID), regionReq.Region)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Regional Pokédex not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, pokedex)
+	s.respondRegionalPokedex(c, regionReq.Region, pokedex)
+}
+
+// respondRegionalPokedex writes a regional Pokédex as stored, or with
+// ?view=decoded as lists of species.
+func (s *Server) respondRegionalPokedex(c *gin.Context, region string, pokedex *models.RegionalPokedex) {
+	switch c.DefaultQuery("view", "raw") {
+	case "raw":
+		c.JSON(http.StatusOK, pokedex)
+	case "decoded":
+		r, _ := s.catalog.Region(region)
+		c.JSON(http.StatusOK, s.decodeRegionalPokedex(r, pokedex))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view, expected raw or decoded"})
+	}
+}
+
+func (s *Server) getPokedexForms(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	entries, err := s.getPokedexFormEntries(s.db, int(playerID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex forms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (s *Server) getPokedexMilestones(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	region := c.Query("region")
+	if region != "" {
+		if _, exists := s.catalog.Region(region); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+	}
+	afterID, _ := strconv.Atoi(c.DefaultQuery("after_id", "0"))
+
+	milestones, err := s.getPokedexMilestonesData(s.db, int(playerID), region, afterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex milestones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (s *Server) getPokedexEntryByID(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	nationalID, err := strconv.Atoi(c.Param("national_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid national ID"})
+		return
+	}
+	species, exists := s.catalog.Species(nationalID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		return
+	}
+
+	entry, err := s.getPokedexEntry(s.db, int(playerID), species)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
 }
 
 func (s *Server) updatePokedex(c *gin.Context) {
@@ -193,7 +310,7 @@ func (s *Server) updatePokedex(c *gin.Context) {
 		return
 	}
 
-	if err := s.updatePokedexEntry(int(playerID), updateReq); err != nil {
+	if err := s.auditedUpdatePokedexEntry(c, int(playerID), updateReq); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Pokédex"})
 		return
 	}
@@ -201,6 +318,16 @@ func (s *Server) updatePokedex(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Pokédex updated successfully"})
 }
 
+func (s *Server) getLivingDexLeaderboard(c *gin.Context) {
+	leaderboard, err := s.getLivingDexLeaderboardData()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
+
 func (s *Server) getPokedexLeaderboard(c *gin.Context) {
 	leaderboard, err := s.getPokedexLeaderboardData()
 	if err != nil {
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"time"
+
+	"pokefactory_server/internal/auth"
+)
+
+// Login tickets are single-use and short-lived: the Minecraft server hands one
+// to the player's client, which exchanges it at /auth/login right away.
+const loginTicketTTL = 2 * time.Minute
+
+func (s *Server) createLoginTicket(playerID int, serverID string) (string, error) {
+	ticket, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	// Clear out tickets that can no longer be used
+	s.db.Exec(`DELETE FROM login_tickets WHERE expires_at < NOW() - INTERVAL '1 day'`)
+
+	query := `
+		INSERT INTO login_tickets (ticket_hash, player_id, server_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())`
+	_, err = s.db.Exec(query, auth.HashOpaqueToken(ticket), playerID, serverID, time.Now().Add(loginTicketTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return ticket, nil
+}
+
+// consumeLoginTicket marks a ticket as used and returns its player ID and the
+// issuing server. The update is conditional so a ticket can only be redeemed once.
+func (s *Server) consumeLoginTicket(ticket string) (int, string, error) {
+	query := `
+		UPDATE login_tickets SET used_at = NOW()
+		WHERE ticket_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING player_id, server_id`
+
+	var playerID int
+	var serverID string
+	err := s.db.QueryRow(query, auth.HashOpaqueToken(ticket)).Scan(&playerID, &serverID)
+	return playerID, serverID, err
+}
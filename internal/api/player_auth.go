@@ -0,0 +1,66 @@
+package api
+
+import (
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	playerTokenTTL   = time.Hour
+	playerRefreshTTL = time.Hour * 24 * 30
+)
+
+func (s *Server) generatePlayerToken(player *models.Player, session *models.AuthSession) (string, error) {
+	return s.keys.Sign(jwt.MapClaims{
+		"uuid":      player.UUID,
+		"player_id": player.ID,
+		"username":  player.Username,
+		"type":      session.SessionType, // "player" or "web"
+		"scope":     session.Scopes,
+		"jti":       session.JTI,
+		"exp":       time.Now().Add(playerTokenTTL).Unix(),
+	})
+}
+
+// issuePlayerTokens starts a new player session and returns its access and
+// refresh tokens. Players never get more access than the server that vouched
+// for them, so their scopes are capped by that server's scopes; the admin
+// scope comes from the player's own role.
+func (s *Server) issuePlayerTokens(player *models.Player, gameServer *models.GameServer) (string, string, error) {
+	scopes := auth.LimitScopes(auth.DefaultScopes, auth.ParseScopes(gameServer.Scopes))
+	if player.Role == auth.RoleAdmin {
+		scopes = append(scopes, auth.ScopeAdmin)
+	}
+
+	session, refreshToken, err := s.createSession(sessionTypePlayer, &player.ID, nil, auth.FormatScopes(scopes), playerRefreshTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := s.generatePlayerToken(player, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
+
+// issueWebTokens starts a web dashboard session for a linked player. Web
+// sessions can only reach the player's own account pages.
+func (s *Server) issueWebTokens(player *models.Player) (string, string, error) {
+	session, refreshToken, err := s.createSession(sessionTypeWeb, &player.ID, nil, auth.ScopeWebAccount, playerRefreshTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := s.generatePlayerToken(player, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, refreshToken, nil
+}
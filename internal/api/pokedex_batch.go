@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+
+	"pokefactory_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	batchStatusApplied = "applied"
+	batchStatusFailed  = "failed"
+)
+
+// Batch endpoint for catch/see events queued by the mod, e.g. while the API
+// was unreachable. Each player's events are applied in one locked
+// transaction with a single completion recompute.
+func (s *Server) serverBatchUpdatePokedex(c *gin.Context) {
+	var req models.ServerPokedexBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Group events by player, keeping each player's events in request order
+	results := make([]models.PokedexBatchResult, len(req.Events))
+	var players []string
+	eventsByPlayer := map[string][]int{}
+	for i, event := range req.Events {
+		results[i] = models.PokedexBatchResult{
+			Index:      i,
+			PlayerUUID: event.PlayerUUID,
+			NationalID: event.NationalID,
+			Action:     event.Action,
+			Status:     batchStatusFailed,
+		}
+
+		switch {
+		case event.PlayerUUID == "":
+			results[i].Error = "player_uuid is required"
+			continue
+		case event.NationalID <= 0:
+			results[i].Error = "national_id is required"
+			continue
+		case !isKnownPokedexAction(event.Action):
+			results[i].Error = "action must be catch, see, uncatch or unsee"
+			continue
+		}
+		if event.Capture != nil {
+			if err := binding.Validator.ValidateStruct(event.Capture); err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+		}
+
+		if _, exists := eventsByPlayer[event.PlayerUUID]; !exists {
+			players = append(players, event.PlayerUUID)
+		}
+		eventsByPlayer[event.PlayerUUID] = append(eventsByPlayer[event.PlayerUUID], i)
+	}
+
+	for _, playerUUID := range players {
+		indexes := eventsByPlayer[playerUUID]
+
+		player, err := s.getPlayerByUUID(playerUUID)
+		if err != nil {
+			for _, i := range indexes {
+				results[i].Error = "Player not found"
+			}
+			continue
+		}
+
+		s.applyPokedexBatch(c, player.ID, req.Events, indexes, results)
+	}
+
+	response := models.PokedexBatchResponse{Results: results}
+	for _, result := range results {
+		if result.Status == batchStatusApplied {
+			response.Applied++
+		} else {
+			response.Failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applyPokedexBatch applies one player's events and fills in their results.
+// Events naming an unknown species or form fail on their own; a database
+// error rolls back and fails all of the player's events.
+func (s *Server) applyPokedexBatch(c *gin.Context, playerID int, events []models.ServerPokedexUpdateRequest, indexes []int, results []models.PokedexBatchResult) {
+	type appliedEvent struct {
+		index  int
+		req    models.PokedexUpdateRequest
+		before *models.PokedexEntryState
+		after  *models.PokedexEntryState
+	}
+	var applied []appliedEvent
+
+	err := s.withPokedexLock(playerID, func(tx *pokedexTx) error {
+		touched := map[string]bool{}
+		for _, i := range indexes {
+			req := models.PokedexUpdateRequest{
+				NationalID: events[i].NationalID,
+				Action:     events[i].Action,
+				Form:       events[i].Form,
+				Shiny:      events[i].Shiny,
+				Capture:    events[i].Capture,
+				OccurredAt: events[i].OccurredAt,
+			}
+			if _, err := s.resolvePokedexTarget(req); err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+
+			before, _ := s.getPokedexEntryState(tx, playerID, req)
+			regions, err := s.applyPokedexEntry(tx, playerID, req)
+			if err != nil {
+				return err
+			}
+			after, _ := s.getPokedexEntryState(tx, playerID, req)
+
+			for _, region := range regions {
+				touched[region] = true
+			}
+			applied = append(applied, appliedEvent{index: i, req: req, before: before, after: after})
+		}
+
+		if len(applied) == 0 {
+			return nil
+		}
+
+		var regions []string
+		for _, region := range s.catalog.Regions() {
+			if touched[region.Name] {
+				regions = append(regions, region.Name)
+			}
+		}
+		return s.updatePokedexCompletion(tx, playerID, regions...)
+	})
+	if err != nil {
+		for _, i := range indexes {
+			if results[i].Error == "" {
+				results[i].Error = "Failed to update Pokédex"
+			}
+		}
+		return
+	}
+
+	for _, event := range applied {
+		results[event.index].Status = batchStatusApplied
+		s.recordAudit(c, playerID, "pokedex."+event.req.Action, event.before, event.after)
+	}
+}
@@ -1,249 +1,997 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
+	"sort"
+	"time"
 
+	"pokefactory_server/internal/catalog"
 	"pokefactory_server/internal/models"
+
+	"github.com/lib/pq"
 )
 
-var regionTables = map[string]string{
-	"kanto":  "player_pokedex_kanto",
-	"johto":  "player_pokedex_johto",
-	"hoenn":  "player_pokedex_hoenn",
-	"sinnoh": "player_pokedex_sinnoh",
-	"unova":  "player_pokedex_unova",
-	"kalos":  "player_pokedex_kalos",
-	"alola":  "player_pokedex_alola",
-	"galar":  "player_pokedex_galar",
-	"hisui":  "player_pokedex_hisui",
-	"paldea": "player_pokedex_paldea",
-}
-
-var regionSizes = map[string]int{
-	"kanto":  151,
-	"johto":  100,
-	"hoenn":  135,
-	"sinnoh": 107,
-	"unova":  156,
-	"kalos":  72,
-	"alola":  88,
-	"galar":  89,
-	"hisui":  7,
-	"paldea": 120,
-}
-
-// National dex number ranges for each region
-var nationalDexRanges = map[string][2]int{
-	"kanto":  {1, 151},
-	"johto":  {152, 251},
-	"hoenn":  {252, 386},
-	"sinnoh": {387, 493},
-	"unova":  {494, 649},
-	"kalos":  {650, 721},
-	"alola":  {722, 809},
-	"galar":  {810, 898},
-	"hisui":  {899, 905},
-	"paldea": {906, 1008},
-}
-
-func (s *Server) getOrCreatePokedexSummary(playerID int) (*models.PokedexSummary, error) {
-	summary, err := s.getPokedexSummaryByID(playerID)
+// dbtx is satisfied by *sql.DB and *sql.Tx, so the Pokédex operations below
+// can run on their own or inside withPokedexLock.
+type dbtx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// withPokedexLock runs fn in a transaction holding the lock on the player's
+// summary row. Every Pokédex write takes this lock, so concurrent updates for
+// one player are applied one after another and the completion figures fn
+// recomputes are committed together with the flags they were computed from.
+func (s *Server) withPokedexLock(playerID int, fn func(tx *pokedexTx) error) error {
+	sqlTx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer sqlTx.Rollback()
+	tx := &pokedexTx{Tx: sqlTx, stats: map[int]speciesStatsDelta{}}
+
+	_, err = tx.Exec(`
+		INSERT INTO player_pokedex_summary (player_id, created_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (player_id) DO NOTHING`, playerID)
+	if err != nil {
+		return err
+	}
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM player_pokedex_summary WHERE player_id = $1 FOR UPDATE`, playerID).Scan(&locked)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := s.applySpeciesStats(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// pokedexTx is the transaction withPokedexLock runs fn in. Flag changes only
+// collect their effect on the shared species_stats counts; the counts are
+// updated once just before commit, in national order, so transactions for
+// different players lock species_stats rows in the same order and cannot
+// deadlock on them.
+type pokedexTx struct {
+	*sql.Tx
+	stats map[int]speciesStatsDelta
+}
+
+type speciesStatsDelta struct {
+	caught, seen int
+}
+
+// countFlagChange records how a species' flags changed for species_stats. A
+// caught species counts as seen, as in getPokedexFlags.
+func (tx *pokedexTx) countFlagChange(nationalID int, wasCaught, wasSeen, isCaught, isSeen bool) {
+	delta := tx.stats[nationalID]
+	delta.caught += flagDelta(wasCaught, isCaught)
+	delta.seen += flagDelta(wasCaught || wasSeen, isCaught || isSeen)
+	tx.stats[nationalID] = delta
+}
+
+func (s *Server) getOrCreatePokedexSummary(q dbtx, playerID int) (*models.PokedexSummary, error) {
+	summary, err := s.getPokedexSummaryByID(q, playerID)
 	if err == nil {
 		return summary, nil
 	}
 
+	// A concurrent withPokedexLock may have created the row in the meantime
 	query := `
 		INSERT INTO player_pokedex_summary (player_id, created_at)
 		VALUES ($1, NOW())
-		RETURNING id, player_id, total_caught, total_seen, regions_completed, national_completion_percentage, last_updated, created_at`
+		ON CONFLICT (player_id) DO UPDATE SET player_id = EXCLUDED.player_id
+		RETURNING id, player_id, total_caught, total_seen, regions_completed, national_completion_percentage, forms_caught, forms_completion_percentage, shiny_caught, shiny_completion_percentage, living_owned, living_completion_percentage, last_updated, created_at`
 
 	summary = &models.PokedexSummary{}
-	err = s.db.QueryRow(query, playerID).Scan(
+	err = q.QueryRow(query, playerID).Scan(
 		&summary.ID, &summary.PlayerID, &summary.TotalCaught, &summary.TotalSeen,
 		&summary.RegionsCompleted, &summary.NationalCompletionPercent,
+		&summary.FormsCaught, &summary.FormsCompletionPercent,
+		&summary.ShinyCaught, &summary.ShinyCompletionPercent,
+		&summary.LivingOwned, &summary.LivingCompletionPercent,
 		&summary.LastUpdated, &summary.CreatedAt,
 	)
 
 	return summary, err
 }
 
-func (s *Server) getPokedexSummaryByID(playerID int) (*models.PokedexSummary, error) {
-	query := `SELECT id, player_id, total_caught, total_seen, regions_completed, national_completion_percentage, last_updated, created_at FROM player_pokedex_summary WHERE player_id = $1`
+func (s *Server) getPokedexSummaryByID(q dbtx, playerID int) (*models.PokedexSummary, error) {
+	query := `SELECT id, player_id, total_caught, total_seen, regions_completed, national_completion_percentage, forms_caught, forms_completion_percentage, shiny_caught, shiny_completion_percentage, living_owned, living_completion_percentage, last_updated, created_at FROM player_pokedex_summary WHERE player_id = $1`
 
 	summary := &models.PokedexSummary{}
-	err := s.db.QueryRow(query, playerID).Scan(
+	err := q.QueryRow(query, playerID).Scan(
 		&summary.ID, &summary.PlayerID, &summary.TotalCaught, &summary.TotalSeen,
 		&summary.RegionsCompleted, &summary.NationalCompletionPercent,
+		&summary.FormsCaught, &summary.FormsCompletionPercent,
+		&summary.ShinyCaught, &summary.ShinyCompletionPercent,
+		&summary.LivingOwned, &summary.LivingCompletionPercent,
 		&summary.LastUpdated, &summary.CreatedAt,
 	)
 
 	return summary, err
 }
 
-func (s *Server) getOrCreateRegionalPokedex(playerID int, region string) (*models.RegionalPokedex, error) {
-	pokedex, err := s.getRegionalPokedexByID(playerID, region)
-	if err == nil {
-		return pokedex, nil
-	}
-
-	tableName, exists := regionTables[region]
+// getRegionalPokedexByID returns a player's progress in one region. Entries
+// are stored per species, so the caught and seen bitfields are built from
+// them in the region's catalog order: bit n is regional number n + 1.
+func (s *Server) getRegionalPokedexByID(q dbtx, playerID int, region string) (*models.RegionalPokedex, error) {
+	r, exists := s.catalog.Region(region)
 	if !exists {
 		return nil, fmt.Errorf("invalid region: %s", region)
 	}
 
-	query := fmt.Sprintf(`
-		INSERT INTO %s (player_id, created_at, updated_at)
-		VALUES ($1, NOW(), NOW())
-		RETURNING id, player_id, caught_flags, seen_flags, completion_date, completion_percentage, created_at, updated_at`, tableName)
-
-	pokedex = &models.RegionalPokedex{}
-	err = s.db.QueryRow(query, playerID).Scan(
-		&pokedex.ID, &pokedex.PlayerID, &pokedex.CaughtFlags, &pokedex.SeenFlags,
-		&pokedex.CompletionDate, &pokedex.CompletionPercentage,
+	query := `
+		SELECT id, player_id, completion_date, completion_percentage, created_at, updated_at
+		FROM player_pokedex_regions
+		WHERE player_id = $1 AND region = $2`
+
+	pokedex := &models.RegionalPokedex{Region: region}
+	err := q.QueryRow(query, playerID, region).Scan(
+		&pokedex.ID, &pokedex.PlayerID, &pokedex.CompletionDate, &pokedex.CompletionPercentage,
 		&pokedex.CreatedAt, &pokedex.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	caught, seen, err := s.getPokedexFlags(q, playerID)
+	if err != nil {
+		return nil, err
+	}
+	pokedex.CaughtFlags = make([]byte, (r.Size+7)/8)
+	pokedex.SeenFlags = make([]byte, (r.Size+7)/8)
+	for i, nationalID := range r.Entries {
+		if caught[nationalID] {
+			pokedex.CaughtFlags = setBit(pokedex.CaughtFlags, i)
+		}
+		if seen[nationalID] {
+			pokedex.SeenFlags = setBit(pokedex.SeenFlags, i)
+		}
+	}
 
-	return pokedex, err
+	return pokedex, nil
 }
 
-func (s *Server) getRegionalPokedexByID(playerID int, region string) (*models.RegionalPokedex, error) {
-	tableName, exists := regionTables[region]
-	if !exists {
-		return nil, fmt.Errorf("invalid region: %s", region)
+// getPokedexFlags returns the national IDs a player has caught and seen. A
+// caught species always counts as seen, so every seen total, bitfield and
+// milestone agrees whichever flag a species was recorded with.
+func (s *Server) getPokedexFlags(q dbtx, playerID int) (caught, seen map[int]bool, err error) {
+	rows, err := q.Query(`
+		SELECT national_id, caught, seen
+		FROM player_pokedex_entries
+		WHERE player_id = $1 AND (caught OR seen)`, playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	caught, seen = map[int]bool{}, map[int]bool{}
+	for rows.Next() {
+		var nationalID int
+		var isCaught, isSeen bool
+		if err := rows.Scan(&nationalID, &isCaught, &isSeen); err != nil {
+			return nil, nil, err
+		}
+		if isCaught {
+			caught[nationalID] = true
+		}
+		if isCaught || isSeen {
+			seen[nationalID] = true
+		}
 	}
 
-	query := fmt.Sprintf(`SELECT id, player_id, caught_flags, seen_flags, completion_date, completion_percentage, created_at, updated_at FROM %s WHERE player_id = $1`, tableName)
+	return caught, seen, rows.Err()
+}
 
-	pokedex := &models.RegionalPokedex{}
-	err := s.db.QueryRow(query, playerID).Scan(
-		&pokedex.ID, &pokedex.PlayerID, &pokedex.CaughtFlags, &pokedex.SeenFlags,
-		&pokedex.CompletionDate, &pokedex.CompletionPercentage,
-		&pokedex.CreatedAt, &pokedex.UpdatedAt,
-	)
+// decodeRegionalPokedex resolves a regional Pokédex's flags to the species
+// they stand for.
+func (s *Server) decodeRegionalPokedex(region catalog.Region, pokedex *models.RegionalPokedex) models.DecodedRegionalPokedex {
+	decoded := models.DecodedRegionalPokedex{
+		Region:               region.Name,
+		DisplayName:          region.DisplayName,
+		Size:                 region.Size,
+		CompletionPercentage: pokedex.CompletionPercentage,
+		CompletionDate:       pokedex.CompletionDate,
+		Caught:               []models.PokedexSpeciesRef{},
+		Seen:                 []models.PokedexSpeciesRef{},
+		Missing:              []models.PokedexSpeciesRef{},
+		UpdatedAt:            pokedex.UpdatedAt,
+	}
 
-	return pokedex, err
+	for i, nationalID := range region.Entries {
+		species, _ := s.catalog.Species(nationalID)
+		ref := models.PokedexSpeciesRef{NationalID: nationalID, RegionalID: i + 1, Name: species.Name}
+		if isBitSet(pokedex.CaughtFlags, i) {
+			decoded.Caught = append(decoded.Caught, ref)
+		} else {
+			decoded.Missing = append(decoded.Missing, ref)
+		}
+		if isBitSet(pokedex.SeenFlags, i) {
+			decoded.Seen = append(decoded.Seen, ref)
+		}
+	}
+
+	decoded.CaughtCount = len(decoded.Caught)
+	decoded.SeenCount = len(decoded.Seen)
+	decoded.MissingCount = len(decoded.Missing)
+	return decoded
 }
 
-func (s *Server) updatePokedexEntry(playerID int, req models.PokedexUpdateRequest) error {
-	var region string
-	var regionalID int
-	var err error
+// isKnownPokedexAction reports whether action is one updatePokedexEntry handles.
+func isKnownPokedexAction(action string) bool {
+	switch action {
+	case "catch", "see", "uncatch", "unsee":
+		return true
+	}
+	return false
+}
 
-	// Determine region and regional ID
+// resolvePokedexTarget determines the species an update request refers to
+// and checks that it has the requested form.
+func (s *Server) resolvePokedexTarget(req models.PokedexUpdateRequest) (catalog.Species, error) {
+	species, err := s.resolvePokedexSpecies(req)
+	if err != nil {
+		return catalog.Species{}, err
+	}
+	if _, exists := species.Form(req.Form); !exists {
+		return catalog.Species{}, fmt.Errorf("%s has no form %q", species.Name, req.Form)
+	}
+	return species, nil
+}
+
+func (s *Server) resolvePokedexSpecies(req models.PokedexUpdateRequest) (catalog.Species, error) {
 	if req.NationalID > 0 {
-		// Use national dex number to find region
-		region, regionalID, err = getRegionFromNationalDex(req.NationalID)
-		if err != nil {
-			return err
+		species, exists := s.catalog.Species(req.NationalID)
+		if !exists {
+			return catalog.Species{}, fmt.Errorf("national dex number %d not found", req.NationalID)
 		}
+		return species, nil
 	} else if req.Region != "" {
 		// Use provided region and pokemon_id as regional ID
-		region = req.Region
-		regionalID = req.PokemonID
-	} else {
-		return fmt.Errorf("either region+pokemon_id or national_id must be provided")
+		if _, exists := s.catalog.Region(req.Region); !exists {
+			return catalog.Species{}, fmt.Errorf("invalid region: %s", req.Region)
+		}
+		nationalID, exists := s.catalog.NationalID(req.Region, req.PokemonID)
+		if !exists {
+			return catalog.Species{}, fmt.Errorf("pokemon_id %d not in the %s Pokédex", req.PokemonID, req.Region)
+		}
+		species, _ := s.catalog.Species(nationalID)
+		return species, nil
+	}
+	return catalog.Species{}, fmt.Errorf("either region+pokemon_id or national_id must be provided")
+}
+
+// getPokedexEntryState reports the caught and seen flags for the species an
+// update request refers to, or for its form entry when it names a form or
+// shiny.
+func (s *Server) getPokedexEntryState(q dbtx, playerID int, req models.PokedexUpdateRequest) (*models.PokedexEntryState, error) {
+	species, err := s.resolvePokedexTarget(req)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &models.PokedexEntryState{NationalID: species.NationalID, Form: req.Form, Shiny: req.Shiny}
+	if req.Form != "" || req.Shiny {
+		entry, err := s.getPokedexFormEntry(q, playerID, species.NationalID, req.Form, req.Shiny)
+		if err == nil {
+			state.Caught, state.Seen = entry.Caught, entry.Seen
+		}
+		return state, nil
+	}
+
+	query := `SELECT caught, seen FROM player_pokedex_entries WHERE player_id = $1 AND national_id = $2`
+	err = q.QueryRow(query, playerID, species.NationalID).Scan(&state.Caught, &state.Seen)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return state, nil
+}
+
+// updatePokedexEntry records a catch or sighting and recomputes completion.
+func (s *Server) updatePokedexEntry(tx *pokedexTx, playerID int, req models.PokedexUpdateRequest) error {
+	regions, err := s.applyPokedexEntry(tx, playerID, req)
+	if err != nil {
+		return err
 	}
 
-	// Get or create regional pokedex
-	pokedex, err := s.getOrCreateRegionalPokedex(playerID, region)
+	// Update completion percentages and summary
+	return s.updatePokedexCompletion(tx, playerID, regions...)
+}
+
+// applyPokedexEntry sets the caught or seen flag for a catch or sighting, or
+// clears it for an uncatch or unsee, and returns the regions listing the
+// species, whose completion is left for the caller to recompute.
+//
+// An uncatch or unsee naming a form or shiny only clears that form entry; one
+// naming just the species clears the species and all of its form entries.
+func (s *Server) applyPokedexEntry(tx *pokedexTx, playerID int, req models.PokedexUpdateRequest) ([]string, error) {
+	if !isKnownPokedexAction(req.Action) {
+		return nil, fmt.Errorf("unknown action %q", req.Action)
+	}
+	species, err := s.resolvePokedexTarget(req)
 	if err != nil {
+		return nil, err
+	}
+
+	// Alternate forms and shinies are also recorded individually
+	if req.Form != "" || req.Shiny {
+		if err := s.recordPokedexForm(tx, playerID, species.NationalID, req); err != nil {
+			return nil, err
+		}
+	}
+
+	regions := make([]string, 0, len(species.Regions))
+	if (req.Action == "uncatch" || req.Action == "unsee") && (req.Form != "" || req.Shiny) {
+		// Only the form entry changes; the forms and shiny dex still need
+		// recomputing
+		return regions, nil
+	}
+
+	if err := s.setPokedexFlag(tx, playerID, species.NationalID, req.Action); err != nil {
+		return nil, err
+	}
+	for _, membership := range species.Regions {
+		regions = append(regions, membership.Region)
+	}
+
+	if req.Action == "uncatch" || req.Action == "unsee" {
+		if err := s.clearPokedexForms(tx, playerID, species.NationalID, req.Action); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.recordPokedexEntry(tx, playerID, species.NationalID, req); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+// setPokedexFlag sets a species' caught or seen flag for a catch or sighting,
+// or clears it for an uncatch or unsee, leaving the other flag as it is.
+func (s *Server) setPokedexFlag(tx *pokedexTx, playerID, nationalID int, action string) error {
+	var caught, seen sql.NullBool
+	switch action {
+	case "catch", "uncatch":
+		caught = sql.NullBool{Bool: action == "catch", Valid: true}
+	case "see", "unsee":
+		seen = sql.NullBool{Bool: action == "see", Valid: true}
+	}
+
+	var wasCaught, wasSeen bool
+	err := tx.QueryRow(`SELECT caught, seen FROM player_pokedex_entries WHERE player_id = $1 AND national_id = $2`,
+		playerID, nationalID).Scan(&wasCaught, &wasSeen)
+	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
 
-	// Update bitfield based on action
-	var updatedFlags []byte
-	if req.Action == "catch" {
-		updatedFlags = setBit(pokedex.CaughtFlags, regionalID-1)
-		if err := s.updateRegionalFlags(playerID, region, "caught_flags", updatedFlags); err != nil {
-			return err
+	query := `
+		INSERT INTO player_pokedex_entries AS e (player_id, national_id, caught, seen, created_at, updated_at)
+		VALUES ($1, $2, COALESCE($3, false), COALESCE($4, false), NOW(), NOW())
+		ON CONFLICT (player_id, national_id) DO UPDATE
+		SET caught = COALESCE($3, e.caught),
+		    seen = COALESCE($4, e.seen),
+		    updated_at = NOW()
+		RETURNING caught, seen`
+	var isCaught, isSeen bool
+	if err := tx.QueryRow(query, playerID, nationalID, caught, seen).Scan(&isCaught, &isSeen); err != nil {
+		return err
+	}
+
+	tx.countFlagChange(nationalID, wasCaught, wasSeen, isCaught, isSeen)
+	return nil
+}
+
+// applySpeciesStats adds the flag changes collected in tx to species_stats.
+func (s *Server) applySpeciesStats(tx *pokedexTx) error {
+	nationalIDs := make([]int, 0, len(tx.stats))
+	for nationalID, delta := range tx.stats {
+		if delta.caught != 0 || delta.seen != 0 {
+			nationalIDs = append(nationalIDs, nationalID)
 		}
-	} else if req.Action == "see" {
-		updatedFlags = setBit(pokedex.SeenFlags, regionalID-1)
-		if err := s.updateRegionalFlags(playerID, region, "seen_flags", updatedFlags); err != nil {
+	}
+	sort.Ints(nationalIDs)
+
+	query := `
+		INSERT INTO species_stats AS st (national_id, catch_count, seen_count, updated_at)
+		VALUES ($1, GREATEST($2, 0), GREATEST($3, 0), NOW())
+		ON CONFLICT (national_id) DO UPDATE
+		SET catch_count = GREATEST(st.catch_count + $2, 0),
+		    seen_count = GREATEST(st.seen_count + $3, 0),
+		    updated_at = NOW()`
+	for _, nationalID := range nationalIDs {
+		delta := tx.stats[nationalID]
+		if _, err := tx.Exec(query, nationalID, delta.caught, delta.seen); err != nil {
 			return err
 		}
 	}
 
-	// Update completion percentage and summary
-	return s.updatePokedexCompletion(playerID, region)
+	tx.stats = map[int]speciesStatsDelta{}
+	return nil
 }
 
-func (s *Server) updateRegionalFlags(playerID int, region, flagType string, flags []byte) error {
-	tableName := regionTables[region]
-	query := fmt.Sprintf(`UPDATE %s SET %s = $1, updated_at = NOW() WHERE player_id = $2`, tableName, flagType)
-	_, err := s.db.Exec(query, flags, playerID)
-	return err
+func flagDelta(was, is bool) int {
+	switch {
+	case is && !was:
+		return 1
+	case was && !is:
+		return -1
+	}
+	return 0
 }
 
-func (s *Server) updatePokedexCompletion(playerID int, region string) error {
+// updatePokedexCompletion recomputes the completion of the given regions and
+// the summary.
+func (s *Server) updatePokedexCompletion(q dbtx, playerID int, regions ...string) error {
 	// Ensure pokedex summary exists for player
-	if _, err := s.getOrCreatePokedexSummary(playerID); err != nil {
+	if _, err := s.getOrCreatePokedexSummary(q, playerID); err != nil {
 		return err
 	}
-	// Calculate regional completion
-	pokedex, err := s.getRegionalPokedexByID(playerID, region)
+
+	caught, seen, err := s.getPokedexFlags(q, playerID)
 	if err != nil {
 		return err
 	}
+	for _, region := range regions {
+		if err := s.updateRegionalCompletion(q, playerID, region, caught, seen); err != nil {
+			return err
+		}
+	}
+	// Update summary
+	return s.updatePokedexSummaryStats(q, playerID)
+}
+
+// updateRegionalCompletion stores a region's completion for the given caught
+// and seen national IDs, keeping the date the region was first completed
+// until it drops below 100% again, and records any milestones reached.
+func (s *Server) updateRegionalCompletion(q dbtx, playerID int, region string, caught, seen map[int]bool) error {
+	r, exists := s.catalog.Region(region)
+	if !exists {
+		return fmt.Errorf("invalid region: %s", region)
+	}
+
+	caughtCount, seenCount := 0, 0
+	for _, nationalID := range r.Entries {
+		if caught[nationalID] {
+			caughtCount++
+		}
+		if seen[nationalID] {
+			seenCount++
+		}
+	}
+	completionPercent := float64(caughtCount) / float64(r.Size) * 100
+	completed := caughtCount == r.Size
+
+	query := `
+		INSERT INTO player_pokedex_regions AS r (player_id, region, completion_percentage, completion_date, created_at, updated_at)
+		VALUES ($1, $2, $3, CASE WHEN $4 THEN NOW() END, NOW(), NOW())
+		ON CONFLICT (player_id, region) DO UPDATE
+		SET completion_percentage = EXCLUDED.completion_percentage,
+		    completion_date = CASE WHEN $4 THEN COALESCE(r.completion_date, NOW()) END,
+		    updated_at = NOW()`
+	if _, err := q.Exec(query, playerID, region, completionPercent, completed); err != nil {
+		return err
+	}
 
-	regionSize := regionSizes[region]
-	caughtCount := countBits(pokedex.CaughtFlags)
-	completionPercent := float64(caughtCount) / float64(regionSize) * 100
+	return s.recordPokedexMilestones(q, playerID, region, r.Size, caughtCount, seenCount)
+}
 
-	// Update regional completion
-	tableName := regionTables[region]
-	query := fmt.Sprintf(`UPDATE %s SET completion_percentage = $1, updated_at = NOW() WHERE player_id = $2`, tableName)
-	if _, err := s.db.Exec(query, completionPercent, playerID); err != nil {
+// recordPokedexMilestones records each configured milestone the player has
+// reached in a region. A milestone is kept once reached, even if entries are
+// later uncaught or reset, so rewards given for it are never handed out twice.
+func (s *Server) recordPokedexMilestones(q dbtx, playerID int, region string, size, caught, seen int) error {
+	if size == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO player_pokedex_milestones (player_id, region, kind, percentage, reached_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (player_id, region, kind, percentage) DO NOTHING`
+
+	for _, progress := range []struct {
+		kind  string
+		count int
+	}{
+		{"caught", caught},
+		{"seen", seen},
+	} {
+		for _, percentage := range s.config.Pokedex.Milestones {
+			if progress.count*100 < percentage*size {
+				break
+			}
+			if _, err := q.Exec(query, playerID, region, progress.kind, percentage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPokedexMilestonesData lists a player's milestones in the order they were
+// reached, optionally for one region and only those after a milestone ID.
+func (s *Server) getPokedexMilestonesData(q dbtx, playerID int, region string, afterID int) ([]models.PokedexMilestone, error) {
+	query := `
+		SELECT id, region, kind, percentage, reached_at
+		FROM player_pokedex_milestones
+		WHERE player_id = $1 AND ($2 = '' OR region = $2) AND id > $3
+		ORDER BY id`
+
+	rows, err := q.Query(query, playerID, region, afterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []models.PokedexMilestone{}
+	for rows.Next() {
+		var milestone models.PokedexMilestone
+		if err := rows.Scan(&milestone.ID, &milestone.Region, &milestone.Kind, &milestone.Percentage, &milestone.ReachedAt); err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, milestone)
+	}
+
+	return milestones, rows.Err()
+}
+
+// recomputePokedex recalculates every regional completion percentage and the
+// summary from the stored entries, dropping completion rows for regions the
+// catalog no longer has.
+func (s *Server) recomputePokedex(q dbtx, playerID int) error {
+	regions := make([]string, 0, len(s.catalog.Regions()))
+	for _, region := range s.catalog.Regions() {
+		regions = append(regions, region.Name)
+	}
+
+	_, err := q.Exec(`DELETE FROM player_pokedex_regions WHERE player_id = $1 AND region <> ALL($2::text[])`,
+		playerID, pq.Array(regions))
+	if err != nil {
 		return err
 	}
-	// Update summary
-	return s.updatePokedexSummaryStats(playerID)
+	return s.updatePokedexCompletion(q, playerID, regions...)
 }
 
-func (s *Server) updatePokedexSummaryStats(playerID int) error {
-	// Calculate totals across all regions
-	totalCaught := 0
-	totalSeen := 0
-	regionsCompleted := 0
+// ApplyCatalogVersion recomputes every player's Pokédex the first time the
+// server runs with a new catalog version, so regional completion, completion
+// dates and milestones follow changes to regions.json without waiting for
+// each player's next update. Milestones already reached are kept. It returns
+// how many players were recomputed, which is zero once the version has been
+// applied.
+func (s *Server) ApplyCatalogVersion() (int, error) {
+	version := s.catalog.Version()
 
-	for region := range regionTables {
-		pokedex, err := s.getRegionalPokedexByID(playerID, region)
+	var applied bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM catalog_versions WHERE version = $1)`, version).Scan(&applied)
+	if err != nil || applied {
+		return 0, err
+	}
+
+	rows, err := s.db.Query(`SELECT player_id FROM player_pokedex_summary ORDER BY player_id`)
+	if err != nil {
+		return 0, err
+	}
+	var playerIDs []int
+	for rows.Next() {
+		var playerID int
+		if err := rows.Scan(&playerID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		playerIDs = append(playerIDs, playerID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Each player is recomputed in their own transaction, so a restart part
+	// way through simply starts over
+	for i, playerID := range playerIDs {
+		err := s.withPokedexLock(playerID, func(tx *pokedexTx) error {
+			return s.recomputePokedex(tx, playerID)
+		})
 		if err != nil {
-			continue
+			return i, fmt.Errorf("player %d: %w", playerID, err)
 		}
+	}
 
-		caughtCount := countBits(pokedex.CaughtFlags)
-		seenCount := countBits(pokedex.SeenFlags)
+	_, err = s.db.Exec(`
+		INSERT INTO catalog_versions (version, applied_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (version) DO NOTHING`, version)
+	return len(playerIDs), err
+}
+
+// resetRegionalPokedex clears the caught and seen flags on every species in
+// one region and on all of their form entries. Unlike an uncatch or unsee,
+// it keeps the species' first seen and first caught times, capture details
+// and owned counts. Flags are stored per species, so other regions listing
+// the same species lose them too; the caller recomputes completion.
+func (s *Server) resetRegionalPokedex(tx *pokedexTx, playerID int, region string) error {
+	r, exists := s.catalog.Region(region)
+	if !exists {
+		return fmt.Errorf("invalid region: %s", region)
+	}
 
-		totalCaught += caughtCount
-		totalSeen += seenCount
+	query := `
+		UPDATE player_pokedex_entries AS e
+		SET caught = false, seen = false, updated_at = NOW()
+		FROM (
+			SELECT national_id, caught, seen FROM player_pokedex_entries
+			WHERE player_id = $1 AND national_id = ANY($2::int[]) AND (caught OR seen)
+			FOR UPDATE
+		) AS previous
+		WHERE e.player_id = $1 AND e.national_id = previous.national_id
+		RETURNING previous.national_id, previous.caught, previous.seen`
+	if err := s.countClearedFlags(tx, query, playerID, pq.Array(r.Entries)); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+		UPDATE player_pokedex_forms SET caught = false, seen = false, updated_at = NOW()
+		WHERE player_id = $1 AND national_id = ANY($2::int[]) AND (caught OR seen)`,
+		playerID, pq.Array(r.Entries))
+	return err
+}
 
-		if pokedex.CompletionPercentage >= 100.0 {
+// deletePokedexEntries deletes a player's entries for the given species, or
+// all of them when nationalIDs is nil, taking them out of species_stats.
+func (s *Server) deletePokedexEntries(tx *pokedexTx, playerID int, nationalIDs []int) error {
+	query := `
+		DELETE FROM player_pokedex_entries
+		WHERE player_id = $1 AND ($2::int[] IS NULL OR national_id = ANY($2::int[]))
+		RETURNING national_id, caught, seen`
+	return s.countClearedFlags(tx, query, playerID, pq.Array(nationalIDs))
+}
+
+// countClearedFlags runs a query clearing entries that returns each one's
+// national ID and previous caught and seen flags, and counts the change for
+// species_stats.
+func (s *Server) countClearedFlags(tx *pokedexTx, query string, args ...interface{}) error {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nationalID int
+		var wasCaught, wasSeen bool
+		if err := rows.Scan(&nationalID, &wasCaught, &wasSeen); err != nil {
+			return err
+		}
+		tx.countFlagChange(nationalID, wasCaught, wasSeen, false, false)
+	}
+	return rows.Err()
+}
+
+func (s *Server) updatePokedexSummaryStats(q dbtx, playerID int) error {
+	// Totals count national species, so one listed in several regional
+	// Pokédexes is only counted once
+	caught, seen, err := s.getPokedexFlags(q, playerID)
+	if err != nil {
+		return err
+	}
+
+	regionsCompleted := 0
+	for _, region := range s.catalog.Regions() {
+		completed := true
+		for _, nationalID := range region.Entries {
+			if !caught[nationalID] {
+				completed = false
+				break
+			}
+		}
+		if completed {
 			regionsCompleted++
 		}
 	}
 
-	nationalPercent := float64(totalCaught) / 1018.0 * 100 // Total Pokémon across all regions
+	totalCaught := len(caught)
+	totalSeen := len(seen)
+	nationalPercent := float64(totalCaught) / float64(s.catalog.Total()) * 100
+
+	// The forms dex credits a species' default form once the species is
+	// caught, plus each alternate form caught; the shiny dex counts species
+	// caught shiny in any form
+	formEntries, err := s.getPokedexFormEntries(q, playerID)
+	if err != nil {
+		return err
+	}
+	alternateForms := map[string]bool{}
+	shinies := map[int]bool{}
+	for _, entry := range formEntries {
+		if !entry.Caught {
+			continue
+		}
+		if entry.Shiny {
+			shinies[entry.NationalID] = true
+		}
+		if entry.Form != "" && entry.FormName != "" {
+			alternateForms[fmt.Sprintf("%d/%s", entry.NationalID, entry.Form)] = true
+		}
+	}
+	formsCaught := totalCaught + len(alternateForms)
+	formsPercent := float64(formsCaught) / float64(s.catalog.TotalForms()) * 100
+	shinyPercent := float64(len(shinies)) / float64(s.catalog.Total()) * 100
+
+	// The living dex counts species the player currently owns at least one of
+	var livingOwned int
+	err = q.QueryRow(`
+		SELECT COUNT(*) FROM player_pokedex_entries
+		WHERE player_id = $1 AND owned_count > 0 AND national_id <= $2`, playerID, s.catalog.Total()).Scan(&livingOwned)
+	if err != nil {
+		return err
+	}
+	livingPercent := float64(livingOwned) / float64(s.catalog.Total()) * 100
 
 	query := `
 		UPDATE player_pokedex_summary 
 		SET total_caught = $1, total_seen = $2, regions_completed = $3, 
-		    national_completion_percentage = $4, last_updated = NOW()
-		WHERE player_id = $5`
+		    national_completion_percentage = $4, forms_caught = $5, forms_completion_percentage = $6,
+		    shiny_caught = $7, shiny_completion_percentage = $8, living_owned = $9,
+		    living_completion_percentage = $10, last_updated = NOW()
+		WHERE player_id = $11`
 
-	_, err := s.db.Exec(query, totalCaught, totalSeen, regionsCompleted, nationalPercent, playerID)
+	_, err = q.Exec(query, totalCaught, totalSeen, regionsCompleted, nationalPercent,
+		formsCaught, formsPercent, len(shinies), shinyPercent, livingOwned, livingPercent, playerID)
 	return err
 }
 
+// recordPokedexForm upserts the form/shiny entry for a catch or sighting, or
+// clears its flag for an uncatch or unsee.
+func (s *Server) recordPokedexForm(q dbtx, playerID, nationalID int, req models.PokedexUpdateRequest) error {
+	switch req.Action {
+	case "uncatch":
+		_, err := q.Exec(`
+			UPDATE player_pokedex_forms SET caught = false, updated_at = NOW()
+			WHERE player_id = $1 AND national_id = $2 AND form = $3 AND shiny = $4`,
+			playerID, nationalID, req.Form, req.Shiny)
+		return err
+	case "unsee":
+		_, err := q.Exec(`
+			UPDATE player_pokedex_forms SET seen = false, updated_at = NOW()
+			WHERE player_id = $1 AND national_id = $2 AND form = $3 AND shiny = $4`,
+			playerID, nationalID, req.Form, req.Shiny)
+		return err
+	}
+
+	caught := req.Action == "catch"
+	seen := req.Action == "see"
+
+	query := `
+		INSERT INTO player_pokedex_forms (player_id, national_id, form, shiny, caught, seen, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (player_id, national_id, form, shiny) DO UPDATE
+		SET caught = player_pokedex_forms.caught OR EXCLUDED.caught,
+		    seen = player_pokedex_forms.seen OR EXCLUDED.seen,
+		    updated_at = NOW()`
+	_, err := q.Exec(query, playerID, nationalID, req.Form, req.Shiny, caught, seen)
+	return err
+}
+
+// clearPokedexForms clears the caught or seen flag on every form entry of a
+// species, for an uncatch or unsee of the species itself.
+func (s *Server) clearPokedexForms(q dbtx, playerID, nationalID int, action string) error {
+	column := "caught"
+	if action == "unsee" {
+		column = "seen"
+	}
+	query := fmt.Sprintf(`
+		UPDATE player_pokedex_forms SET %s = false, updated_at = NOW()
+		WHERE player_id = $1 AND national_id = $2 AND %s`, column, column)
+	_, err := q.Exec(query, playerID, nationalID)
+	return err
+}
+
+// resetPokedexForms clears a player's form and shiny entries.
+func (s *Server) resetPokedexForms(q dbtx, playerID int) error {
+	_, err := q.Exec(`DELETE FROM player_pokedex_forms WHERE player_id = $1`, playerID)
+	return err
+}
+
+func (s *Server) getPokedexFormEntry(q dbtx, playerID, nationalID int, form string, shiny bool) (*models.PokedexFormEntry, error) {
+	query := `
+		SELECT national_id, form, shiny, caught, seen, updated_at
+		FROM player_pokedex_forms
+		WHERE player_id = $1 AND national_id = $2 AND form = $3 AND shiny = $4`
+
+	entry := &models.PokedexFormEntry{}
+	err := q.QueryRow(query, playerID, nationalID, form, shiny).Scan(
+		&entry.NationalID, &entry.Form, &entry.Shiny, &entry.Caught, &entry.Seen, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.nameFormEntry(entry)
+	return entry, nil
+}
+
+// getPokedexFormEntries lists a player's form and shiny entries in national order.
+func (s *Server) getPokedexFormEntries(q dbtx, playerID int) ([]models.PokedexFormEntry, error) {
+	query := `
+		SELECT national_id, form, shiny, caught, seen, updated_at
+		FROM player_pokedex_forms
+		WHERE player_id = $1
+		ORDER BY national_id, form, shiny`
+
+	rows, err := q.Query(query, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.PokedexFormEntry{}
+	for rows.Next() {
+		var entry models.PokedexFormEntry
+		if err := rows.Scan(&entry.NationalID, &entry.Form, &entry.Shiny, &entry.Caught, &entry.Seen, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		s.nameFormEntry(&entry)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nameFormEntry fills in species and form names from the catalog. Forms
+// since removed from the catalog keep an empty form name.
+func (s *Server) nameFormEntry(entry *models.PokedexFormEntry) {
+	species, exists := s.catalog.Species(entry.NationalID)
+	if !exists {
+		return
+	}
+	entry.Name = species.Name
+	if form, exists := species.Form(entry.Form); exists {
+		entry.FormName = form.Name
+	}
+}
+
+// recordPokedexEntry keeps the first seen and first caught times for a
+// species, and the capture details of the first catch. Queued events can
+// arrive late, so an event older than the stored one replaces it. An uncatch
+// or unsee of the species forgets the matching time.
+func (s *Server) recordPokedexEntry(q dbtx, playerID, nationalID int, req models.PokedexUpdateRequest) error {
+	at := time.Now()
+	if req.OccurredAt != nil && req.OccurredAt.Before(at) {
+		at = *req.OccurredAt
+	}
+
+	switch req.Action {
+	case "see":
+		query := `
+			INSERT INTO player_pokedex_entries AS e (player_id, national_id, first_seen_at, created_at, updated_at)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			ON CONFLICT (player_id, national_id) DO UPDATE
+			SET first_seen_at = LEAST(e.first_seen_at, EXCLUDED.first_seen_at),
+			    updated_at = NOW()`
+		_, err := q.Exec(query, playerID, nationalID, at)
+		return err
+
+	case "catch":
+		capture := req.Capture
+		if capture == nil {
+			capture = &models.PokedexCapture{}
+		}
+
+		// Every capture column is taken from the earlier of the two catches
+		query := `
+			INSERT INTO player_pokedex_entries AS e (
+				player_id, national_id, first_caught_at, dimension, biome, x, y, z, ball, level, form, shiny,
+				created_at, updated_at
+			)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, NULLIF($9, ''), NULLIF($10, 0), $11, $12, NOW(), NOW())
+			ON CONFLICT (player_id, national_id) DO UPDATE
+			SET dimension = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.dimension ELSE e.dimension END,
+			    biome = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.biome ELSE e.biome END,
+			    x = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.x ELSE e.x END,
+			    y = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.y ELSE e.y END,
+			    z = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.z ELSE e.z END,
+			    ball = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.ball ELSE e.ball END,
+			    level = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.level ELSE e.level END,
+			    form = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.form ELSE e.form END,
+			    shiny = CASE WHEN e.first_caught_at IS NULL OR EXCLUDED.first_caught_at < e.first_caught_at THEN EXCLUDED.shiny ELSE e.shiny END,
+			    first_caught_at = LEAST(e.first_caught_at, EXCLUDED.first_caught_at),
+			    updated_at = NOW()`
+		_, err := q.Exec(query, playerID, nationalID, at,
+			capture.Dimension, capture.Biome, capture.X, capture.Y, capture.Z, capture.Ball, capture.Level,
+			req.Form, req.Shiny,
+		)
+		return err
+
+	case "uncatch":
+		if req.Form != "" || req.Shiny {
+			return nil
+		}
+		query := `
+			UPDATE player_pokedex_entries
+			SET first_caught_at = NULL, dimension = NULL, biome = NULL, x = NULL, y = NULL, z = NULL,
+			    ball = NULL, level = NULL, form = NULL, shiny = NULL, updated_at = NOW()
+			WHERE player_id = $1 AND national_id = $2`
+		_, err := q.Exec(query, playerID, nationalID)
+		return err
+
+	case "unsee":
+		if req.Form != "" || req.Shiny {
+			return nil
+		}
+		query := `
+			UPDATE player_pokedex_entries SET first_seen_at = NULL, updated_at = NOW()
+			WHERE player_id = $1 AND national_id = $2`
+		_, err := q.Exec(query, playerID, nationalID)
+		return err
+	}
+
+	return nil
+}
+
+// resetPokedexEntries deletes all of a player's species entries: flags,
+// journal and ownership.
+func (s *Server) resetPokedexEntries(tx *pokedexTx, playerID int) error {
+	return s.deletePokedexEntries(tx, playerID, nil)
+}
+
+// getPokedexEntry returns a player's journal entry for one species. Species
+// never seen or caught get an entry with no times.
+func (s *Server) getPokedexEntry(q dbtx, playerID int, species catalog.Species) (*models.PokedexEntry, error) {
+	state, err := s.getPokedexEntryState(q, playerID, models.PokedexUpdateRequest{NationalID: species.NationalID})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.PokedexEntry{
+		NationalID: species.NationalID,
+		Name:       species.Name,
+		Caught:     state.Caught,
+		Seen:       state.Caught || state.Seen,
+	}
+
+	query := `
+		SELECT owned_count, first_seen_at, first_caught_at, COALESCE(form, ''), COALESCE(shiny, false),
+		       COALESCE(dimension, ''), COALESCE(biome, ''), x, y, z, COALESCE(ball, ''), COALESCE(level, 0)
+		FROM player_pokedex_entries
+		WHERE player_id = $1 AND national_id = $2`
+
+	capture := &models.PokedexCapture{}
+	err = q.QueryRow(query, playerID, species.NationalID).Scan(
+		&entry.OwnedCount, &entry.FirstSeenAt, &entry.FirstCaughtAt, &entry.CaughtForm, &entry.CaughtShiny,
+		&capture.Dimension, &capture.Biome, &capture.X, &capture.Y, &capture.Z, &capture.Ball, &capture.Level,
+	)
+	if err == sql.ErrNoRows {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.FirstCaughtAt != nil {
+		entry.Capture = capture
+	}
+	return entry, nil
+}
+
 func (s *Server) getPokedexLeaderboardData() ([]models.LeaderboardEntry, error) {
 	query := `
 		SELECT ps.player_id, p.username, ps.national_completion_percentage, ps.total_caught
 		FROM player_pokedex_summary ps
 		JOIN players p ON ps.player_id = p.id
+		WHERE p.privacy = $1
 		ORDER BY ps.national_completion_percentage DESC, ps.total_caught DESC
 		LIMIT 50`
 
-	rows, err := s.db.Query(query)
+	// Only public players are listed
+	rows, err := s.db.Query(query, models.PrivacyPublic)
 	if err != nil {
 		return nil, err
 	}
@@ -261,6 +1009,61 @@ func (s *Server) getPokedexLeaderboardData() ([]models.LeaderboardEntry, error)
 	return leaderboard, nil
 }
 
+// setPokedexOwnership stores how many of each species a player currently
+// owns. Unless partial, the counts are a full snapshot and every species not
+// listed is set to zero. The caller recomputes the summary.
+func (s *Server) setPokedexOwnership(q dbtx, playerID int, counts []models.PokedexOwnership, partial bool) error {
+	if !partial {
+		_, err := q.Exec(`
+			UPDATE player_pokedex_entries SET owned_count = 0, owned_updated_at = NOW()
+			WHERE player_id = $1 AND owned_count > 0`, playerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO player_pokedex_entries (player_id, national_id, owned_count, owned_updated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW(), NOW())
+		ON CONFLICT (player_id, national_id) DO UPDATE
+		SET owned_count = EXCLUDED.owned_count, owned_updated_at = NOW(), updated_at = NOW()`
+	for _, count := range counts {
+		if _, err := q.Exec(query, playerID, count.NationalID, count.Count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) getLivingDexLeaderboardData() ([]models.LivingDexLeaderboardEntry, error) {
+	query := `
+		SELECT ps.player_id, p.username, ps.living_completion_percentage, ps.living_owned
+		FROM player_pokedex_summary ps
+		JOIN players p ON ps.player_id = p.id
+		WHERE p.privacy = $1 AND ps.living_owned > 0
+		ORDER BY ps.living_completion_percentage DESC, p.username
+		LIMIT 50`
+
+	// Only public players are listed
+	rows, err := s.db.Query(query, models.PrivacyPublic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leaderboard := []models.LivingDexLeaderboardEntry{}
+	for rows.Next() {
+		var entry models.LivingDexLeaderboardEntry
+		if err := rows.Scan(&entry.PlayerID, &entry.Username, &entry.LivingCompletionPercent, &entry.LivingOwned); err != nil {
+			continue
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+
+	return leaderboard, nil
+}
+
 func setBit(data []byte, position int) []byte {
 	if len(data) == 0 {
 		data = make([]byte, (position/8)+1)
@@ -279,23 +1082,10 @@ func setBit(data []byte, position int) []byte {
 	return data
 }
 
-func countBits(data []byte) int {
-	count := 0
-	for _, b := range data {
-		for b != 0 {
-			count += int(b & 1)
-			b >>= 1
-		}
-	}
-	return count
-}
-
-func getRegionFromNationalDex(nationalID int) (string, int, error) {
-	for region, rangeData := range nationalDexRanges {
-		if nationalID >= rangeData[0] && nationalID <= rangeData[1] {
-			regionalID := nationalID - rangeData[0] + 1
-			return region, regionalID, nil
-		}
+func isBitSet(data []byte, position int) bool {
+	byteIndex := position / 8
+	if position < 0 || byteIndex >= len(data) {
+		return false
 	}
-	return "", 0, fmt.Errorf("national dex number %d not found in any region", nationalID)
+	return data[byteIndex]&(1<<(position%8)) != 0
 }
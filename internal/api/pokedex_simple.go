@@ -13,8 +13,11 @@ func (s *Server) updatePokedexSimple(c *gin.Context) {
 	playerID := c.GetFloat64("player_id")
 	
 	var simpleReq struct {
-		NationalID int    `json:"national_id" binding:"required"`
-		Action     string `json:"action" binding:"required"` // "catch" or "see"
+		NationalID int                    `json:"national_id" binding:"required"`
+		Action     string                 `json:"action" binding:"required,oneof=catch see"`
+		Form       string                 `json:"form,omitempty"`
+		Shiny      bool                   `json:"shiny,omitempty"`
+		Capture    *models.PokedexCapture `json:"capture,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&simpleReq); err != nil {
@@ -26,9 +29,12 @@ func (s *Server) updatePokedexSimple(c *gin.Context) {
 	req := models.PokedexUpdateRequest{
 		NationalID: simpleReq.NationalID,
 		Action:     simpleReq.Action,
+		Form:       simpleReq.Form,
+		Shiny:      simpleReq.Shiny,
+		Capture:    simpleReq.Capture,
 	}
 
-	if err := s.updatePokedexEntry(int(playerID), req); err != nil {
+	if err := s.auditedUpdatePokedexEntry(c, int(playerID), req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Pokédex"})
 		return
 	}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"pokefactory_server/internal/catalog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listPokemon returns the species catalog, optionally filtered by region,
+// type and generation.
+func (s *Server) listPokemon(c *gin.Context) {
+	// A region filter lists its species in regional order
+	candidates := s.catalog.All()
+	if region := c.Query("region"); region != "" {
+		r, exists := s.catalog.Region(region)
+		if !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+		candidates = make([]catalog.Species, 0, r.Size)
+		for _, nationalID := range r.Entries {
+			sp, _ := s.catalog.Species(nationalID)
+			candidates = append(candidates, sp)
+		}
+	}
+
+	typ := c.Query("type")
+	if typ != "" && !catalog.IsKnownType(typ) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type"})
+		return
+	}
+
+	generation := 0
+	if value := c.Query("generation"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid generation"})
+			return
+		}
+		generation = parsed
+	}
+
+	species := []catalog.Species{}
+	for _, sp := range candidates {
+		if typ != "" && !sp.HasType(typ) {
+			continue
+		}
+		if generation != 0 && sp.Generation != generation {
+			continue
+		}
+		species = append(species, sp)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": s.catalog.Version(),
+		"count":   len(species),
+		"pokemon": species,
+	})
+}
+
+// getPokemon looks up one species by national dex number or name.
+func (s *Server) getPokemon(c *gin.Context) {
+	species, exists := s.catalog.Lookup(c.Param("id"))
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, species)
+}
+
+// listRegions returns the regional Pokédexes and their sizes.
+func (s *Server) listRegions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version": s.catalog.Version(),
+		"regions": s.catalog.Regions(),
+	})
+}
@@ -1,92 +1,244 @@
 package api
 
 import (
+	"crypto/tls"
 	"database/sql"
+	"net/http"
 
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/catalog"
 	"pokefactory_server/internal/config"
 	"pokefactory_server/internal/middleware"
+	"pokefactory_server/internal/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
-	db     *sql.DB
-	config *config.Config
-	router *gin.Engine
+	db        *sql.DB
+	config    *config.Config
+	keys      *auth.KeySet
+	limiter   *ratelimit.Limiter
+	catalog   *catalog.Catalog
+	router    *gin.Engine // Server proxy, player and admin API
+	webRouter *gin.Engine // Public web dashboard; nil when served by router
 }
 
-func NewServer(db *sql.DB, cfg *config.Config) *Server {
+func NewServer(db *sql.DB, cfg *config.Config, keys *auth.KeySet, limiter *ratelimit.Limiter, dex *catalog.Catalog) *Server {
 	server := &Server{
-		db:     db,
-		config: cfg,
-		router: gin.Default(),
+		db:      db,
+		config:  cfg,
+		keys:    keys,
+		limiter: limiter,
+		catalog: dex,
+		router:  newRouter(cfg),
+	}
+
+	server.setupRoutes(server.router)
+
+	// With a web port configured the dashboard gets its own listener and the
+	// API listener stops serving it; otherwise both share one listener
+	if cfg.Server.WebPort != "" {
+		server.webRouter = newRouter(cfg)
+		server.setupWebRoutes(server.webRouter)
+	} else {
+		server.setupWebRoutes(server.router)
 	}
 
-	server.setupRoutes()
 	return server
 }
 
-func (s *Server) setupRoutes() {
+func newRouter(cfg *config.Config) *gin.Engine {
+	router := gin.Default()
+
+	// Only trust X-Forwarded-For from configured proxies, otherwise clients
+	// could pick their own IP and dodge per-IP rate limits
+	router.SetTrustedProxies(cfg.Server.TrustedProxies)
+
+	return router
+}
+
+// setupRoutes registers the server proxy, player and admin API.
+func (s *Server) setupRoutes(router *gin.Engine) {
 	// Health check endpoint
-	s.router.GET("/health", s.healthCheck)
+	router.GET("/health", s.healthCheck)
+
+	// Public verification keys for services validating PokéFactory tokens
+	router.GET("/.well-known/jwks.json", s.getJWKS)
 
 	// API v1 routes
-	v1 := s.router.Group("/api/v1")
+	v1 := router.Group("/api/v1")
 	{
 		// Public routes
-		v1.POST("/auth/login", s.login)
-		v1.POST("/server/auth", s.serverAuth)
+		loginLimit := middleware.RateLimit(s.limiter.Store, s.limiter.Limits.Login, middleware.ClientIPKey("login"))
+		v1.POST("/auth/login", loginLimit, s.login)
+		v1.POST("/auth/refresh", loginLimit, s.refreshToken)
+		v1.POST("/server/auth", middleware.RateLimit(s.limiter.Store, s.limiter.Limits.ServerAuth, middleware.ClientIPKey("server-auth")), s.serverAuth)
+
+		// Species catalog (public)
+		catalogLimit := middleware.RateLimit(s.limiter.Store, s.limiter.Limits.Web, middleware.ClientIPKey("catalog"))
+		v1.GET("/pokemon", catalogLimit, s.listPokemon)
+		v1.GET("/pokemon/:id", catalogLimit, s.getPokemon)
+		v1.GET("/regions", catalogLimit, s.listRegions)
 		
 		// Protected routes
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(s.config.JWT.Secret))
+		protected.Use(middleware.AuthMiddleware(s.keys.Keyfunc, s.isSessionActive))
+		protected.Use(middleware.RateLimit(s.limiter.Store, s.limiter.Limits.PlayerRequests, middleware.ContextKey("player", "player_id")))
 		{
 			// Player routes
-			protected.GET("/player/profile", s.getPlayerProfile)
-			protected.PUT("/player/profile", s.updatePlayerProfile)
-			protected.GET("/player/stats", s.getPlayerStats)
-			protected.PUT("/player/stats", s.updatePlayerStats)
-			protected.GET("/player/data/:key", s.getPlayerData)
-			protected.PUT("/player/data/:key", s.setPlayerData)
-			
+			playerRead := protected.Group("", middleware.RequireScopes(auth.ScopePlayerRead))
+			playerRead.GET("/player/profile", s.getPlayerProfile)
+			playerRead.GET("/player/stats", s.getPlayerStats)
+			playerRead.GET("/player/data/:key", s.getPlayerData)
+			playerRead.GET("/player/privacy", s.getPlayerPrivacy)
+
+			playerWrite := protected.Group("", middleware.RequireScopes(auth.ScopePlayerWrite))
+			playerWrite.PUT("/player/profile", s.updatePlayerProfile)
+			playerWrite.PUT("/player/stats", s.updatePlayerStats)
+			playerWrite.PUT("/player/data/:key", s.setPlayerData)
+			playerWrite.PUT("/player/privacy", s.updatePlayerPrivacySetting)
+
+			playerRead.GET("/player/sessions", s.getPlayerSessions)
+			playerWrite.DELETE("/player/sessions/:id", s.revokePlayerSessionByID)
+
 			// Pokédex routes
-			protected.GET("/pokedex/summary", s.getPokedexSummary)
-			protected.POST("/pokedex/region", s.getRegionalPokedex)
-			protected.PUT("/pokedex/update", s.updatePokedex)
-			protected.PUT("/pokedex/catch", s.updatePokedexSimple) // Simplified national dex endpoint
-			protected.GET("/pokedex/leaderboard", s.getPokedexLeaderboard)
+			pokedexRead := protected.Group("", middleware.RequireScopes(auth.ScopePokedexRead))
+			pokedexRead.GET("/pokedex/summary", s.getPokedexSummary)
+			pokedexRead.POST("/pokedex/region", s.getRegionalPokedex)
+			pokedexRead.GET("/pokedex/forms", s.getPokedexForms)
+			pokedexRead.GET("/pokedex/entry/:national_id", s.getPokedexEntryByID)
+			pokedexRead.GET("/pokedex/milestones", s.getPokedexMilestones)
+			pokedexRead.GET("/pokedex/leaderboard", s.getPokedexLeaderboard)
+			pokedexRead.GET("/pokedex/leaderboard/living", s.getLivingDexLeaderboard)
+
+			pokedexWrite := protected.Group("", middleware.RequireScopes(auth.ScopePokedexWrite))
+			pokedexWrite.PUT("/pokedex/update", s.updatePokedex)
+			pokedexWrite.PUT("/pokedex/catch", s.updatePokedexSimple) // Simplified national dex endpoint
 		}
-		
+
 		// Server proxy routes (for Minecraft server communication)
 		server := v1.Group("/server")
-		server.Use(middleware.ServerAuthMiddleware(s.config.JWT.Secret))
+		server.Use(middleware.ServerAuthMiddleware(s.keys.Keyfunc, s.isSessionActive))
+		server.Use(middleware.RequestSignature(s.serverSigningPolicy, s.recordRequestNonce, s.config.Signing.MaxSkew))
+		server.Use(middleware.RateLimit(s.limiter.Store, s.limiter.Limits.ServerRequests, middleware.ContextKey("server", "server_id")))
 		{
 			// Player management
-			server.POST("/player/get", s.serverGetPlayer)
-			server.POST("/player/create", s.serverCreateOrUpdatePlayer)
-			server.POST("/player/stats/get", s.serverGetPlayerStats)
-			server.POST("/player/stats/update", s.serverUpdatePlayerStats)
-			server.POST("/player/data/get", s.serverGetPlayerData)
-			server.POST("/player/data/set", s.serverSetPlayerData)
-			
+			playerRead := server.Group("", middleware.RequireScopes(auth.ScopePlayerRead))
+			playerRead.POST("/player/get", s.serverGetPlayer)
+			playerRead.POST("/player/stats/get", s.serverGetPlayerStats)
+			playerRead.POST("/player/data/get", s.serverGetPlayerData)
+
+			playerWrite := server.Group("", middleware.RequireScopes(auth.ScopePlayerWrite))
+			playerWrite.POST("/player/create", s.serverCreateOrUpdatePlayer)
+			playerWrite.POST("/player/login-ticket", s.serverIssueLoginTicket)
+			playerWrite.POST("/player/web-link-code", s.serverIssueWebLinkCode)
+			playerWrite.POST("/player/stats/update", s.serverUpdatePlayerStats)
+			playerWrite.POST("/player/data/set", s.serverSetPlayerData)
+
 			// Pokédex management
-			server.POST("/pokedex/summary", s.serverGetPokedexSummary)
-			server.POST("/pokedex/region", s.serverGetRegionalPokedex)
-			server.POST("/pokedex/update", s.serverUpdatePokedex)
-			server.GET("/pokedex/leaderboard", s.getPokedexLeaderboard)
+			pokedexRead := server.Group("", middleware.RequireScopes(auth.ScopePokedexRead))
+			pokedexRead.POST("/pokedex/summary", s.serverGetPokedexSummary)
+			pokedexRead.POST("/pokedex/region", s.serverGetRegionalPokedex)
+			pokedexRead.POST("/pokedex/forms", s.serverGetPokedexForms)
+			pokedexRead.POST("/pokedex/entry", s.serverGetPokedexEntry)
+			pokedexRead.POST("/pokedex/milestones", s.serverGetPokedexMilestones)
+			pokedexRead.GET("/pokedex/leaderboard", s.getPokedexLeaderboard)
+			pokedexRead.GET("/pokedex/leaderboard/living", s.getLivingDexLeaderboard)
+
+			pokedexWrite := server.Group("", middleware.RequireScopes(auth.ScopePokedexWrite))
+			pokedexWrite.POST("/pokedex/update", s.serverUpdatePokedex)
+			pokedexWrite.POST("/pokedex/batch", s.serverBatchUpdatePokedex)
+			pokedexWrite.POST("/pokedex/reset", s.serverResetPokedex)
+			pokedexWrite.POST("/pokedex/ownership", s.serverUpdatePokedexOwnership)
+
+			// Session management
+			server.GET("/sessions", s.serverGetSessions)
+			server.DELETE("/sessions/:id", s.serverRevokeSession)
 		}
 		
-		// Web dashboard routes (public - for web frontend)
-		web := v1.Group("/web")
+		// Admin routes (moderation and maintenance) - player or server tokens with the admin scope
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(s.keys.Keyfunc, s.isSessionActive), middleware.RequireScopes(auth.ScopeAdmin))
 		{
-			web.GET("/leaderboards", s.getWebLeaderboards)
-			web.GET("/player/:username/stats", s.getWebPlayerStats)
-			web.GET("/server/analytics", s.getWebServerAnalytics)
-			web.GET("/pokemon/:dex/popularity", s.getWebPokemonPopularity)
+			admin.GET("/players", s.adminSearchPlayers)
+			admin.GET("/players/:id", s.adminGetPlayer)
+			admin.PUT("/players/:id/stats", s.adminUpdatePlayerStats)
+			admin.PUT("/players/:id/username", s.adminRenamePlayer)
+			admin.PUT("/players/:id/role", s.adminSetPlayerRole)
+			admin.PUT("/players/:id/pokedex", s.adminUpdatePokedex)
+			admin.POST("/players/:id/pokedex/reset", s.adminResetPokedex)
+			admin.POST("/players/:id/pokedex/recompute", s.adminRecomputePokedex)
+			admin.GET("/audit", s.adminGetAuditLog)
 		}
 	}
 }
 
-func (s *Server) Run(addr string) error {
-	return s.router.Run(addr)
-}
\ No newline at end of file
+// setupWebRoutes registers the public web dashboard routes. When the web
+// listener is separate, its router serves nothing else.
+func (s *Server) setupWebRoutes(router *gin.Engine) {
+	if router != s.router {
+		router.GET("/health", s.healthCheck)
+		router.GET("/.well-known/jwks.json", s.getJWKS)
+	}
+
+	// Web dashboard routes (public - for web frontend)
+	web := router.Group("/api/v1/web")
+	web.Use(middleware.RateLimit(s.limiter.Store, s.limiter.Limits.Web, middleware.ClientIPKey("web")))
+	{
+		web.GET("/leaderboards", s.getWebLeaderboards)
+		web.GET("/player/:username/stats", s.getWebPlayerStats)
+		web.GET("/server/analytics", s.getWebServerAnalytics)
+		web.GET("/pokemon/popularity", s.getWebPokemonPopularityList)
+		web.GET("/pokemon/:dex/popularity", s.getWebPokemonPopularity)
+
+		// Web accounts linked to players with an in-game code
+		loginLimit := middleware.RateLimit(s.limiter.Store, s.limiter.Limits.Login, middleware.ClientIPKey("web-login"))
+		web.POST("/auth/link", loginLimit, s.webLink)
+		web.POST("/auth/login", loginLimit, s.webLogin)
+		web.POST("/auth/refresh", loginLimit, s.webRefreshToken)
+
+		account := web.Group("/account")
+		account.Use(middleware.AuthMiddleware(s.keys.Keyfunc, s.isSessionActive), middleware.RequireScopes(auth.ScopeWebAccount))
+		{
+			account.GET("", s.getWebAccount)
+			account.PUT("/password", s.updateWebAccountPassword)
+			account.PUT("/privacy", s.updatePlayerPrivacySetting)
+			account.POST("/logout", s.webLogout)
+		}
+	}
+}
+
+// Run serves the API listener, over HTTPS when TLS is configured, and the
+// web listener when one is configured. It returns when either stops.
+func (s *Server) Run() error {
+	tlsConfig, err := buildTLSConfig(s.config.TLS)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- serve(s.config.Server.Host+":"+s.config.Server.Port, s.router, tlsConfig)
+	}()
+	if s.webRouter != nil {
+		go func() {
+			errs <- serve(s.config.Server.WebHost+":"+s.config.Server.WebPort, s.webRouter, nil)
+		}()
+	}
+
+	return <-errs
+}
+
+func serve(addr string, handler http.Handler, tlsConfig *tls.Config) error {
+	httpServer := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	if tlsConfig == nil {
+		return httpServer.ListenAndServe()
+	}
+	// Certificates are already loaded into TLSConfig
+	return httpServer.ListenAndServeTLS("", "")
+}
@@ -3,15 +3,37 @@ package api
 import (
 	"time"
 
+	"pokefactory_server/internal/models"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func (s *Server) generateServerToken(serverID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"server_id": serverID,
+const (
+	serverTokenTTL   = time.Hour * 24
+	serverRefreshTTL = time.Hour * 24 * 30
+)
+
+func (s *Server) generateServerToken(gameServer *models.GameServer, jti string) (string, error) {
+	return s.keys.Sign(jwt.MapClaims{
+		"server_id": gameServer.ServerID,
 		"type":      "server",
-		"exp":       time.Now().Add(time.Hour * 24 * 30).Unix(), // 30 days for server tokens
+		"scope":     gameServer.Scopes,
+		"jti":       jti,
+		"exp":       time.Now().Add(serverTokenTTL).Unix(),
 	})
+}
+
+// issueServerTokens starts a new server session and returns its access and refresh tokens.
+func (s *Server) issueServerTokens(gameServer *models.GameServer) (string, string, error) {
+	session, refreshToken, err := s.createSession(sessionTypeServer, nil, &gameServer.ServerID, gameServer.Scopes, serverRefreshTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := s.generateServerToken(gameServer, session.JTI)
+	if err != nil {
+		return "", "", err
+	}
 
-	return token.SignedString([]byte(s.config.JWT.Secret))
-}
\ No newline at end of file
+	return token, refreshToken, nil
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/middleware"
 	"pokefactory_server/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -16,20 +19,57 @@ func (s *Server) serverAuth(c *gin.Context) {
 		return
 	}
 
-	// Validate server credentials (you can customize this logic)
-	if authReq.ServerKey != s.config.JWT.Secret {
+	// A verified client certificate identifies the server by its CN and
+	// stands in for the server key
+	certServerID := middleware.ClientCertServerID(c)
+	if certServerID != "" {
+		if authReq.ServerID == "" {
+			authReq.ServerID = certServerID
+		} else if authReq.ServerID != certServerID {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Client certificate does not match server_id"})
+			return
+		}
+	} else if authReq.ServerID == "" || authReq.ServerKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "server_id and server_key are required"})
+		return
+	}
+
+	// Lock out a server_id/IP pair after repeated failures. Keying on both
+	// stops a remote attacker from locking out the real server.
+	failureKey := "server-auth-failures:" + authReq.ServerID + ":" + c.ClientIP()
+	failureRule := s.limiter.Limits.AuthFailures
+	if result, err := s.limiter.Store.Take(failureKey, failureRule, 0); err == nil && !result.Allowed {
+		middleware.TooManyRequests(c, result.RetryAfter, "Too many failed authentication attempts")
+		return
+	}
+
+	// Validate server credentials against the game server registry
+	gameServer, err := s.getGameServerByServerID(authReq.ServerID)
+	if err != nil || (certServerID == "" && !auth.VerifyServerKey(authReq.ServerKey, gameServer.KeyHash)) {
+		s.limiter.Store.Take(failureKey, failureRule, 1)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid server credentials"})
 		return
 	}
 
+	if !gameServer.Enabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Server access disabled"})
+		return
+	}
+
+	s.updateGameServerLastAuth(gameServer.ServerID)
+
 	// Generate server JWT token
-	token, err := s.generateServerToken(authReq.ServerID)
+	token, refreshToken, err := s.issueServerTokens(gameServer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate server token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, gin.H{
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(serverTokenTTL.Seconds()),
+	})
 }
 
 // Server proxy endpoints for player operations
@@ -56,7 +96,7 @@ func (s *Server) serverCreateOrUpdatePlayer(c *gin.Context) {
 		return
 	}
 
-	player, err := s.getOrCreatePlayer(req.PlayerUUID, req.Username)
+	player, err := s.auditedGetOrCreatePlayer(c, req.PlayerUUID, req.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create/update player"})
 		return
@@ -65,6 +105,60 @@ func (s *Server) serverCreateOrUpdatePlayer(c *gin.Context) {
 	c.JSON(http.StatusOK, player)
 }
 
+// serverIssueLoginTicket creates (or refreshes) the player and returns a
+// short-lived, single-use ticket the player's client can redeem at /auth/login.
+func (s *Server) serverIssueLoginTicket(c *gin.Context) {
+	var req models.ServerPlayerUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player, err := s.auditedGetOrCreatePlayer(c, req.PlayerUUID, req.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create/update player"})
+		return
+	}
+
+	ticket, err := s.createLoginTicket(player.ID, c.GetString("server_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create login ticket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticket":     ticket,
+		"expires_in": int(loginTicketTTL.Seconds()),
+	})
+}
+
+// serverIssueWebLinkCode returns a short code for the player to type into the
+// web dashboard, linking a web account to their player (see webLink).
+func (s *Server) serverIssueWebLinkCode(c *gin.Context) {
+	var req models.ServerPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	code, err := s.createWebLinkCode(player.ID, c.GetString("server_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":       code,
+		"expires_in": int(webLinkCodeTTL.Seconds()),
+	})
+}
+
 func (s *Server) serverGetPlayerStats(c *gin.Context) {
 	var req models.ServerPlayerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -101,7 +195,7 @@ func (s *Server) serverUpdatePlayerStats(c *gin.Context) {
 	}
 
 	req.Stats.PlayerID = player.ID
-	if err := s.updatePlayerStatsByID(req.Stats); err != nil {
+	if err := s.auditedUpdatePlayerStats(c, req.Stats); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stats"})
 		return
 	}
@@ -144,7 +238,7 @@ func (s *Server) serverSetPlayerData(c *gin.Context) {
 		return
 	}
 
-	if err := s.setPlayerDataByKey(player.ID, req.DataKey, req.DataValue); err != nil {
+	if err := s.auditedSetPlayerData(c, player.ID, req.DataKey, req.DataValue); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set data"})
 		return
 	}
@@ -165,7 +259,7 @@ func (s *Server) serverGetPokedexSummary(c *gin.Context) {
 		return
 	}
 
-	summary, err := s.getPokedexSummaryByID(player.ID)
+	summary, err := s.getPokedexSummaryByID(s.db, player.ID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Pokédex summary not found"})
 		return
@@ -174,6 +268,141 @@ func (s *Server) serverGetPokedexSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, summary)
 }
 
+func (s *Server) serverGetPokedexForms(c *gin.Context) {
+	var req models.ServerPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	entries, err := s.getPokedexFormEntries(s.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex forms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (s *Server) serverResetPokedex(c *gin.Context) {
+	var req models.ServerPokedexResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Region != "" {
+		if _, exists := s.catalog.Region(req.Region); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	if err := s.auditedResetPokedex(c, player.ID, req.Region); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset Pokédex"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pokédex reset successfully"})
+}
+
+func (s *Server) serverGetPokedexMilestones(c *gin.Context) {
+	var req models.ServerPokedexMilestonesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Region != "" {
+		if _, exists := s.catalog.Region(req.Region); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	milestones, err := s.getPokedexMilestonesData(s.db, player.ID, req.Region, req.AfterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex milestones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (s *Server) serverUpdatePokedexOwnership(c *gin.Context) {
+	var req models.ServerPokedexOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, count := range req.Counts {
+		if _, exists := s.catalog.Species(count.NationalID); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid national ID " + strconv.Itoa(count.NationalID)})
+			return
+		}
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	if err := s.auditedSetPokedexOwnership(c, player.ID, req.Counts, req.Partial); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ownership"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ownership updated successfully"})
+}
+
+func (s *Server) serverGetPokedexEntry(c *gin.Context) {
+	var req models.ServerPokedexEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	species, exists := s.catalog.Species(req.NationalID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		return
+	}
+
+	player, err := s.getPlayerByUUID(req.PlayerUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	entry, err := s.getPokedexEntry(s.db, player.ID, species)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokédex entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
 func (s *Server) serverGetRegionalPokedex(c *gin.Context) {
 	var req models.ServerPokedexRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -187,13 +416,13 @@ func (s *Server) serverGetRegionalPokedex(c *gin.Context) {
 		return
 	}
 
-	pokedex, err := s.getRegionalPokedexByID(player.ID, req.Region)
+	pokedex, err := s.getRegionalPokedexByID(s.db, player.ID, req.Region)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Regional Pokédex not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, pokedex)
+	s.respondRegionalPokedex(c, req.Region, pokedex)
 }
 
 func (s *Server) serverUpdatePokedex(c *gin.Context) {
@@ -212,9 +441,13 @@ func (s *Server) serverUpdatePokedex(c *gin.Context) {
 	updateReq := models.PokedexUpdateRequest{
 		NationalID: req.NationalID,
 		Action:     req.Action,
+		Form:       req.Form,
+		Shiny:      req.Shiny,
+		Capture:    req.Capture,
+		OccurredAt: req.OccurredAt,
 	}
 
-	if err := s.updatePokedexEntry(player.ID, updateReq); err != nil {
+	if err := s.auditedUpdatePokedexEntry(c, player.ID, updateReq); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Pokédex"})
 		return
 	}
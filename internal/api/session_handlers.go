@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"pokefactory_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// refreshToken exchanges a refresh token for a new access/refresh token pair.
+// Works for both player and server sessions.
+func (s *Server) refreshToken(c *gin.Context) {
+	s.refreshSession(c, sessionTypePlayer, sessionTypeServer)
+}
+
+// webRefreshToken is the web listener's refresh endpoint; it only accepts
+// web dashboard sessions.
+func (s *Server) webRefreshToken(c *gin.Context) {
+	s.refreshSession(c, sessionTypeWeb)
+}
+
+func (s *Server) refreshSession(c *gin.Context, sessionTypes ...string) {
+	var req models.TokenRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, refreshToken, err := s.rotateSession(req.RefreshToken, sessionTypes...)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	var token string
+	var expiresIn int
+	switch session.SessionType {
+	case sessionTypeServer:
+		gameServer, lookupErr := s.getGameServerByServerID(*session.ServerID)
+		if lookupErr != nil || !gameServer.Enabled {
+			s.revokeServerSession(*session.ServerID, session.ID)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Server access disabled"})
+			return
+		}
+		token, err = s.generateServerToken(gameServer, session.JTI)
+		expiresIn = int(serverTokenTTL.Seconds())
+	case sessionTypePlayer, sessionTypeWeb:
+		player, lookupErr := s.getPlayerByID(*session.PlayerID)
+		if lookupErr != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Player not found"})
+			return
+		}
+		token, err = s.generatePlayerToken(player, session)
+		expiresIn = int(playerTokenTTL.Seconds())
+	}
+
+	if err != nil || token == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    expiresIn,
+	})
+}
+
+func (s *Server) getPlayerSessions(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	sessions, err := s.getActivePlayerSessions(int(playerID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sessions"})
+		return
+	}
+
+	markCurrentSession(sessions, c.GetString("token_id"))
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (s *Server) revokePlayerSessionByID(c *gin.Context) {
+	playerID := c.GetFloat64("player_id")
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	revoked, err := s.revokePlayerSession(int(playerID), sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+func (s *Server) serverGetSessions(c *gin.Context) {
+	sessions, err := s.getActiveServerSessions(c.GetString("server_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sessions"})
+		return
+	}
+
+	markCurrentSession(sessions, c.GetString("token_id"))
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (s *Server) serverRevokeSession(c *gin.Context) {
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	revoked, err := s.revokeServerSession(c.GetString("server_id"), sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+func markCurrentSession(sessions []models.AuthSession, jti string) {
+	for i := range sessions {
+		sessions[i].Current = sessions[i].JTI == jti
+	}
+}
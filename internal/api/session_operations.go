@@ -0,0 +1,171 @@
+package api
+
+import (
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	sessionTypePlayer = "player"
+	sessionTypeServer = "server"
+	sessionTypeWeb    = "web" // Web dashboard login of a linked player
+)
+
+const sessionColumns = `id, jti, session_type, player_id, server_id, scopes, refresh_expires_at, revoked_at, last_used_at, created_at`
+
+// createSession records a new login session and returns it together with the
+// plain refresh token. The session's jti is embedded in the access token so
+// the middleware can reject tokens whose session has been revoked.
+func (s *Server) createSession(sessionType string, playerID *int, serverID *string, scopes string, refreshTTL time.Duration) (*models.AuthSession, string, error) {
+	jti, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, "", err
+	}
+	refreshToken, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Clear out sessions that can no longer be refreshed
+	s.db.Exec(`DELETE FROM auth_sessions WHERE refresh_expires_at < NOW() - INTERVAL '7 days'`)
+
+	query := `
+		INSERT INTO auth_sessions (jti, session_type, player_id, server_id, scopes, refresh_token_hash, refresh_expires_at, last_used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING ` + sessionColumns
+
+	session, err := scanSession(s.db.QueryRow(query, jti, sessionType, playerID, serverID, scopes,
+		auth.HashOpaqueToken(refreshToken), time.Now().Add(refreshTTL)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, refreshToken, nil
+}
+
+// rotateSession redeems a refresh token for one of the given session types:
+// the session gets a new jti (which invalidates the previous access token)
+// and a new refresh token.
+func (s *Server) rotateSession(refreshToken string, sessionTypes ...string) (*models.AuthSession, string, error) {
+	jti, err := auth.GenerateOpaqueToken(16)
+	if err != nil {
+		return nil, "", err
+	}
+	newRefreshToken, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := `
+		UPDATE auth_sessions
+		SET jti = $1, refresh_token_hash = $2, last_used_at = NOW()
+		WHERE refresh_token_hash = $3 AND session_type = ANY($4) AND revoked_at IS NULL AND refresh_expires_at > NOW()
+		RETURNING ` + sessionColumns
+
+	session, err := scanSession(s.db.QueryRow(query, jti, auth.HashOpaqueToken(newRefreshToken),
+		auth.HashOpaqueToken(refreshToken), pq.Array(sessionTypes)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, newRefreshToken, nil
+}
+
+// isSessionActive is used by the auth middlewares to check a token's jti.
+func (s *Server) isSessionActive(jti string) bool {
+	query := `SELECT EXISTS (SELECT 1 FROM auth_sessions WHERE jti = $1 AND revoked_at IS NULL AND refresh_expires_at > NOW())`
+
+	var active bool
+	if err := s.db.QueryRow(query, jti).Scan(&active); err != nil {
+		return false
+	}
+	return active
+}
+
+func (s *Server) getActivePlayerSessions(playerID int) ([]models.AuthSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM auth_sessions
+		WHERE player_id = $1 AND revoked_at IS NULL AND refresh_expires_at > NOW()
+		ORDER BY last_used_at DESC`
+	return s.querySessions(query, playerID)
+}
+
+func (s *Server) getActiveServerSessions(serverID string) ([]models.AuthSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM auth_sessions
+		WHERE server_id = $1 AND revoked_at IS NULL AND refresh_expires_at > NOW()
+		ORDER BY last_used_at DESC`
+	return s.querySessions(query, serverID)
+}
+
+func (s *Server) revokePlayerSession(playerID, sessionID int) (bool, error) {
+	query := `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND player_id = $2 AND revoked_at IS NULL`
+	return s.execAffected(query, sessionID, playerID)
+}
+
+// revokeSessionByJTI ends the session behind the caller's own access token.
+func (s *Server) revokeSessionByJTI(jti string) (bool, error) {
+	query := `UPDATE auth_sessions SET revoked_at = NOW() WHERE jti = $1 AND revoked_at IS NULL`
+	return s.execAffected(query, jti)
+}
+
+// revokeOtherWebSessions ends a player's web sessions except the caller's own.
+func (s *Server) revokeOtherWebSessions(playerID int, jti string) error {
+	query := `
+		UPDATE auth_sessions SET revoked_at = NOW()
+		WHERE player_id = $1 AND session_type = $2 AND jti <> $3 AND revoked_at IS NULL`
+	_, err := s.db.Exec(query, playerID, sessionTypeWeb, jti)
+	return err
+}
+
+func (s *Server) revokeServerSession(serverID string, sessionID int) (bool, error) {
+	query := `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND server_id = $2 AND revoked_at IS NULL`
+	return s.execAffected(query, sessionID, serverID)
+}
+
+func (s *Server) querySessions(query string, args ...interface{}) ([]models.AuthSession, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.AuthSession{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+func (s *Server) execAffected(query string, args ...interface{}) (bool, error) {
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row rowScanner) (*models.AuthSession, error) {
+	session := &models.AuthSession{}
+	err := row.Scan(
+		&session.ID, &session.JTI, &session.SessionType, &session.PlayerID, &session.ServerID,
+		&session.Scopes, &session.RefreshExpiresAt, &session.RevokedAt, &session.LastUsedAt, &session.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/middleware"
+	"pokefactory_server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webLink redeems a link code shown to the player in-game (see
+// serverIssueWebLinkCode), binds a web account to the player and starts a
+// web session. A password may be set at the same time to allow later logins
+// without a new code.
+func (s *Server) webLink(c *gin.Context) {
+	var req models.WebLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Lock out an IP after repeated wrong codes, as for logins
+	failureKey := "web-link-failures:" + c.ClientIP()
+	failureRule := s.limiter.Limits.AuthFailures
+	if result, err := s.limiter.Store.Take(failureKey, failureRule, 0); err == nil && !result.Allowed {
+		middleware.TooManyRequests(c, result.RetryAfter, "Too many failed link attempts")
+		return
+	}
+
+	playerID, err := s.consumeWebLinkCode(req.Code)
+	if err != nil {
+		s.limiter.Store.Take(failureKey, failureRule, 1)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired link code"})
+		return
+	}
+
+	var passwordHash *string
+	if req.Password != "" {
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link account"})
+			return
+		}
+		passwordHash = &hash
+	}
+
+	if _, err := s.linkWebAccount(playerID, passwordHash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link account"})
+		return
+	}
+
+	s.startWebSession(c, playerID)
+}
+
+// webLogin signs in a linked player with the password set on their web account.
+func (s *Server) webLogin(c *gin.Context) {
+	var req models.WebLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Lock out a username/IP pair after repeated failures, as for server auth
+	failureKey := "web-login-failures:" + strings.ToLower(req.Username) + ":" + c.ClientIP()
+	failureRule := s.limiter.Limits.AuthFailures
+	if result, err := s.limiter.Store.Take(failureKey, failureRule, 0); err == nil && !result.Allowed {
+		middleware.TooManyRequests(c, result.RetryAfter, "Too many failed login attempts")
+		return
+	}
+
+	playerID, ok := s.verifyWebLogin(req.Username, req.Password)
+	if !ok {
+		s.limiter.Store.Take(failureKey, failureRule, 1)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+		return
+	}
+
+	s.startWebSession(c, playerID)
+}
+
+// startWebSession issues web tokens for a player and writes the response.
+func (s *Server) startWebSession(c *gin.Context, playerID int) {
+	player, err := s.getPlayerByID(playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+		return
+	}
+	s.updateWebAccountLogin(player.ID)
+
+	token, refreshToken, err := s.issueWebTokens(player)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(playerTokenTTL.Seconds()),
+		"player":        player,
+	})
+}
+
+func (s *Server) getWebAccount(c *gin.Context) {
+	playerID := int(c.GetFloat64("player_id"))
+
+	player, err := s.getPlayerByID(playerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	account, err := s.getWebAccountByPlayerID(playerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Web account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebAccountResponse{
+		Player:      *player,
+		HasPassword: account.PasswordHash != nil,
+		LinkedAt:    account.LinkedAt,
+		LastLoginAt: account.LastLoginAt,
+	})
+}
+
+func (s *Server) updateWebAccountPassword(c *gin.Context) {
+	playerID := int(c.GetFloat64("player_id"))
+
+	var req models.WebPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := s.getWebAccountByPlayerID(playerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Web account not found"})
+		return
+	}
+
+	// A stolen web token alone must not be enough to take over the account
+	if account.PasswordHash != nil {
+		failureKey := "web-password-failures:" + strconv.Itoa(playerID)
+		failureRule := s.limiter.Limits.AuthFailures
+		if result, err := s.limiter.Store.Take(failureKey, failureRule, 0); err == nil && !result.Allowed {
+			middleware.TooManyRequests(c, result.RetryAfter, "Too many failed password attempts")
+			return
+		}
+		if req.CurrentPassword == "" || !auth.VerifyPassword(req.CurrentPassword, *account.PasswordHash) {
+			s.limiter.Store.Take(failureKey, failureRule, 1)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+
+	if err := s.setWebAccountPassword(playerID, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+	// Sign out every other web session, e.g. one that leaked the old password
+	if err := s.revokeOtherWebSessions(playerID, c.GetString("token_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end other sessions"})
+		return
+	}
+	s.recordAudit(c, playerID, "web.password.update",
+		gin.H{"has_password": account.PasswordHash != nil}, gin.H{"has_password": true})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+}
+
+// webLogout ends the caller's web session.
+func (s *Server) webLogout(c *gin.Context) {
+	if _, err := s.revokeSessionByJTI(c.GetString("token_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
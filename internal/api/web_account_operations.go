@@ -0,0 +1,125 @@
+package api
+
+import (
+	"time"
+
+	"pokefactory_server/internal/auth"
+	"pokefactory_server/internal/models"
+)
+
+// Link codes are shown to the player in-game and typed into the web
+// dashboard, so they live a little longer than login tickets.
+const webLinkCodeTTL = 10 * time.Minute
+
+const webAccountColumns = `id, player_id, password_hash, linked_at, last_login_at, created_at, updated_at`
+
+// createWebLinkCode issues a link code for a player, replacing any unused
+// code the player already had.
+func (s *Server) createWebLinkCode(playerID int, serverID string) (string, error) {
+	code, err := auth.GenerateLinkCode()
+	if err != nil {
+		return "", err
+	}
+
+	// Clear out codes that can no longer be used
+	s.db.Exec(`DELETE FROM web_link_codes WHERE expires_at < NOW() - INTERVAL '1 day'`)
+	s.db.Exec(`UPDATE web_link_codes SET used_at = NOW() WHERE player_id = $1 AND used_at IS NULL`, playerID)
+
+	query := `
+		INSERT INTO web_link_codes (code_hash, player_id, server_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())`
+	_, err = s.db.Exec(query, auth.HashOpaqueToken(auth.NormalizeLinkCode(code)), playerID, serverID, time.Now().Add(webLinkCodeTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// consumeWebLinkCode marks a code as used and returns its player ID.
+func (s *Server) consumeWebLinkCode(code string) (int, error) {
+	query := `
+		UPDATE web_link_codes SET used_at = NOW()
+		WHERE code_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING player_id`
+
+	var playerID int
+	err := s.db.QueryRow(query, auth.HashOpaqueToken(auth.NormalizeLinkCode(code))).Scan(&playerID)
+	return playerID, err
+}
+
+// linkWebAccount binds a web account to a player. A nil password hash keeps
+// any password the account already has.
+func (s *Server) linkWebAccount(playerID int, passwordHash *string) (*models.WebAccount, error) {
+	query := `
+		INSERT INTO web_accounts (player_id, password_hash, linked_at, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW(), NOW())
+		ON CONFLICT (player_id)
+		DO UPDATE SET password_hash = COALESCE(EXCLUDED.password_hash, web_accounts.password_hash),
+			linked_at = NOW(), updated_at = NOW()
+		RETURNING ` + webAccountColumns
+
+	return scanWebAccount(s.db.QueryRow(query, playerID, passwordHash))
+}
+
+func (s *Server) getWebAccountByPlayerID(playerID int) (*models.WebAccount, error) {
+	query := `SELECT ` + webAccountColumns + ` FROM web_accounts WHERE player_id = $1`
+	return scanWebAccount(s.db.QueryRow(query, playerID))
+}
+
+// verifyWebLogin returns the player whose web account matches username and
+// password. Player usernames are not unique (a stale row can still hold a
+// name another player has since taken), so only players with a password set
+// are considered, and a name shared by two such accounts matches neither.
+func (s *Server) verifyWebLogin(username, password string) (int, bool) {
+	query := `
+		SELECT ` + webAccountColumns + ` FROM web_accounts
+		WHERE password_hash IS NOT NULL
+		  AND player_id IN (SELECT id FROM players WHERE username = $1)
+		LIMIT 2`
+
+	rows, err := s.db.Query(query, username)
+	if err != nil {
+		return 0, false
+	}
+	defer rows.Close()
+
+	var accounts []*models.WebAccount
+	for rows.Next() {
+		account, err := scanWebAccount(rows)
+		if err != nil {
+			return 0, false
+		}
+		accounts = append(accounts, account)
+	}
+	if rows.Err() != nil || len(accounts) != 1 {
+		return 0, false
+	}
+
+	account := accounts[0]
+	return account.PlayerID, auth.VerifyPassword(password, *account.PasswordHash)
+}
+
+func (s *Server) setWebAccountPassword(playerID int, passwordHash string) error {
+	query := `UPDATE web_accounts SET password_hash = $1, updated_at = NOW() WHERE player_id = $2`
+	_, err := s.db.Exec(query, passwordHash, playerID)
+	return err
+}
+
+func (s *Server) updateWebAccountLogin(playerID int) error {
+	query := `UPDATE web_accounts SET last_login_at = NOW() WHERE player_id = $1`
+	_, err := s.db.Exec(query, playerID)
+	return err
+}
+
+func scanWebAccount(row rowScanner) (*models.WebAccount, error) {
+	account := &models.WebAccount{}
+	err := row.Scan(
+		&account.ID, &account.PlayerID, &account.PasswordHash, &account.LinkedAt,
+		&account.LastLoginAt, &account.CreatedAt, &account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 
 	"pokefactory_server/internal/models"
@@ -22,8 +23,9 @@ func (s *Server) getWebLeaderboards(c *gin.Context) {
 func (s *Server) getWebPlayerStats(c *gin.Context) {
 	username := c.Param("username")
 	
+	// Private players are indistinguishable from unknown ones
 	player, err := s.getPlayerByUsername(username)
-	if err != nil {
+	if err != nil || player.Privacy == models.PrivacyPrivate {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
 		return
 	}
@@ -34,7 +36,7 @@ func (s *Server) getWebPlayerStats(c *gin.Context) {
 		return
 	}
 
-	pokedex, err := s.getPokedexSummaryByID(player.ID)
+	pokedex, err := s.getPokedexSummaryByID(s.db, player.ID)
 	if err != nil {
 		// Create empty pokedex if not found
 		pokedex = &models.PokedexSummary{PlayerID: player.ID}
@@ -84,4 +86,78 @@ func (s *Server) getWebPokemonPopularity(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, popularity)
-}
\ No newline at end of file
+}
+
+// getWebPokemonPopularityList ranks species by how many players have caught
+// them. order=rarest lists the least caught first; tier and region filter the
+// list and limit/offset page through it.
+func (s *Server) getWebPokemonPopularityList(c *gin.Context) {
+	order := c.DefaultQuery("order", "popular")
+	if order != "popular" && order != "rarest" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order, expected popular or rarest"})
+		return
+	}
+	tier := c.Query("tier")
+	if tier != "" && !models.IsKnownRarityTier(tier) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rarity tier"})
+		return
+	}
+	region := c.Query("region")
+	if region != "" {
+		if _, exists := s.catalog.Region(region); !exists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+			return
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	popularity, totalPlayers, err := s.getSpeciesPopularity()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Pokemon popularity"})
+		return
+	}
+
+	if order == "rarest" {
+		sort.SliceStable(popularity, func(i, j int) bool {
+			return popularity[i].CatchCount < popularity[j].CatchCount
+		})
+	}
+
+	pokemon := []models.WebPokemonPopularity{}
+	for _, entry := range popularity {
+		if tier != "" && entry.RarityTier != tier {
+			continue
+		}
+		if region != "" {
+			species, _ := s.catalog.Species(entry.NationalID)
+			if _, listed := species.RegionalID(region); !listed {
+				continue
+			}
+		}
+		pokemon = append(pokemon, entry)
+	}
+
+	count := len(pokemon)
+	if offset > count {
+		offset = count
+	}
+	if end := offset + limit; end < count {
+		pokemon = pokemon[offset:end]
+	} else {
+		pokemon = pokemon[offset:]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total_players": totalPlayers,
+		"count":         count,
+		"pokemon":       pokemon,
+	})
+}
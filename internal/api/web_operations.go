@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"sort"
 
 	"pokefactory_server/internal/models"
 )
@@ -13,10 +14,12 @@ func (s *Server) getWebLeaderboardData() ([]models.WebLeaderboard, error) {
 		FROM players p
 		JOIN player_stats ps ON p.id = ps.player_id
 		LEFT JOIN player_pokedex_summary pds ON p.id = pds.player_id
+		WHERE p.privacy = $1
 		ORDER BY pds.national_completion_percentage DESC, ps.level DESC, pds.total_caught DESC
 		LIMIT 100`
 
-	rows, err := s.db.Query(query)
+	// Only public players are listed
+	rows, err := s.db.Query(query, models.PrivacyPublic)
 	if err != nil {
 		return nil, err
 	}
@@ -88,20 +91,28 @@ func (s *Server) getServerAnalyticsData() (*models.WebServerAnalytics, error) {
 }
 
 func (s *Server) getMostPopularRegion() string {
-	regions := []string{"kanto", "johto", "hoenn", "sinnoh", "unova", "alola", "galar", "hisui", "paldea"}
 	maxCompletion := 0.0
-	topRegion := "kanto"
+	topRegion := s.catalog.Regions()[0].Name
+
+	// Highest average completion among players who have started the region
+	rows, err := s.db.Query(`
+		SELECT region, AVG(completion_percentage)
+		FROM player_pokedex_regions
+		WHERE completion_percentage > 0
+		GROUP BY region
+		ORDER BY region`)
+	if err != nil {
+		return topRegion
+	}
+	defer rows.Close()
 
-	for _, region := range regions {
-		tableName := regionTables[region]
+	for rows.Next() {
+		var region string
 		var avgCompletion float64
-		
-		query := fmt.Sprintf(`
-			SELECT COALESCE(AVG(completion_percentage), 0) 
-			FROM %s WHERE completion_percentage > 0`, tableName)
-		
-		err := s.db.QueryRow(query).Scan(&avgCompletion)
-		if err == nil && avgCompletion > maxCompletion {
+		if err := rows.Scan(&region, &avgCompletion); err != nil {
+			continue
+		}
+		if _, exists := s.catalog.Region(region); exists && avgCompletion > maxCompletion {
 			maxCompletion = avgCompletion
 			topRegion = region
 		}
@@ -109,61 +120,99 @@ func (s *Server) getMostPopularRegion() string {
 
 	return topRegion
 }
-
 func (s *Server) getPokemonPopularityData(nationalID int) (*models.WebPokemonPopularity, error) {
-	region, regionalID, err := getRegionFromNationalDex(nationalID)
+	if _, exists := s.catalog.Species(nationalID); !exists {
+		return nil, fmt.Errorf("national dex number %d not found", nationalID)
+	}
+
+	popularity, _, err := s.getSpeciesPopularity()
 	if err != nil {
 		return nil, err
 	}
 
-	popularity := &models.WebPokemonPopularity{
-		NationalID: nationalID,
-		Region:     region,
+	for i := range popularity {
+		if popularity[i].NationalID == nationalID {
+			return &popularity[i], nil
+		}
 	}
+	return nil, fmt.Errorf("national dex number %d not found", nationalID)
+}
 
-	// Count how many players have caught this Pokemon
-	tableName := regionTables[region]
-	bitPosition := regionalID - 1
-
-	// This is a simplified query - in production you'd use proper bitwise operations
-	query := fmt.Sprintf(`
-		SELECT COUNT(*) as catch_count,
-		       (SELECT COUNT(*) FROM %s WHERE LENGTH(caught_flags) > %d) as seen_count,
-		       (SELECT COUNT(*) FROM %s) as total_players
-		FROM %s 
-		WHERE LENGTH(caught_flags) > %d`, 
-		tableName, bitPosition/8, tableName, tableName, bitPosition/8)
-
+// getSpeciesPopularity ranks every catalog species by how many players have
+// caught it, using the species_stats aggregate, and returns the ranking with
+// the number of players it is out of. Ties share a rank and keep national
+// order, so species nobody has caught rank last together.
+func (s *Server) getSpeciesPopularity() ([]models.WebPokemonPopularity, int, error) {
+	// Every player with a Pokédex
 	var totalPlayers int
-	err = s.db.QueryRow(query).Scan(&popularity.CatchCount, &popularity.SeenCount, &totalPlayers)
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM player_pokedex_summary`).Scan(&totalPlayers); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`SELECT national_id, catch_count, seen_count FROM species_stats`)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	defer rows.Close()
 
-	// Calculate catch rate percentage
-	if totalPlayers > 0 {
-		popularity.CatchRate = float64(popularity.CatchCount) / float64(totalPlayers) * 100
+	type speciesCounts struct{ caught, seen int }
+	counts := map[int]speciesCounts{}
+	for rows.Next() {
+		var nationalID int
+		var c speciesCounts
+		if err := rows.Scan(&nationalID, &c.caught, &c.seen); err != nil {
+			return nil, 0, err
+		}
+		counts[nationalID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	// Get popularity rank (simplified - would need more complex ranking logic)
-	popularity.PopularityRank = s.calculatePopularityRank(nationalID, popularity.CatchCount)
+	popularity := make([]models.WebPokemonPopularity, 0, s.catalog.Total())
+	for _, species := range s.catalog.All() {
+		entry := models.WebPokemonPopularity{
+			NationalID: species.NationalID,
+			Name:       species.Name,
+			CatchCount: counts[species.NationalID].caught,
+			SeenCount:  counts[species.NationalID].seen,
+		}
+		// Reported under the first regional Pokédex listing the species
+		if len(species.Regions) > 0 {
+			entry.Region = species.Regions[0].Region
+		}
+		if totalPlayers > 0 {
+			entry.CatchRate = float64(entry.CatchCount) / float64(totalPlayers) * 100
+		}
+		entry.RarityTier = rarityTier(entry.CatchCount, entry.CatchRate)
+		popularity = append(popularity, entry)
+	}
+
+	sort.SliceStable(popularity, func(i, j int) bool {
+		return popularity[i].CatchCount > popularity[j].CatchCount
+	})
+	for i := range popularity {
+		if i > 0 && popularity[i].CatchCount == popularity[i-1].CatchCount {
+			popularity[i].PopularityRank = popularity[i-1].PopularityRank
+		} else {
+			popularity[i].PopularityRank = i + 1
+		}
+	}
 
-	return popularity, nil
+	return popularity, totalPlayers, nil
 }
 
-func (s *Server) calculatePopularityRank(nationalID, catchCount int) int {
-	// Simplified ranking - in production would rank against all Pokemon
-	var rank int
-	query := `
-		SELECT COUNT(*) + 1 FROM (
-			SELECT 1 FROM player_pokedex_summary 
-			WHERE total_caught > $1
-		) as higher_counts`
-	
-	err := s.db.QueryRow(query, catchCount).Scan(&rank)
-	if err != nil {
-		return 999 // Default rank if calculation fails
+// rarityTier buckets a species by the share of players who have caught it.
+func rarityTier(catchCount int, catchRate float64) string {
+	switch {
+	case catchCount == 0:
+		return models.RarityUncaught
+	case catchRate >= 50:
+		return models.RarityCommon
+	case catchRate >= 20:
+		return models.RarityUncommon
+	case catchRate >= 5:
+		return models.RarityRare
 	}
-	
-	return rank
-}
\ No newline at end of file
+	return models.RarityVeryRare
+}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"pokefactory_server/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySet holds the key used to sign new tokens and every key accepted when
+// verifying them. With no key files configured it falls back to HS256 with
+// JWT_SECRET, which is what single-instance deployments have always used.
+type KeySet struct {
+	signing      *key
+	verification map[string]*key
+	hmacSecret   []byte
+}
+
+type key struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// JWK is a single public key in JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeySet reads the signing key and any additional verification keys
+// (previous or upcoming keys during a rotation) from the configured PEM files.
+func LoadKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	keySet := &KeySet{verification: map[string]*key{}}
+
+	if cfg.SigningKeyFile == "" {
+		keySet.hmacSecret = []byte(cfg.Secret)
+		return keySet, nil
+	}
+
+	signing, err := loadPrivateKey(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	keySet.signing = signing
+	keySet.verification[signing.id] = signing
+
+	for _, path := range cfg.VerificationKeyFiles {
+		verification, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		keySet.verification[verification.id] = verification
+	}
+
+	return keySet, nil
+}
+
+// Sign signs the claims with the current signing key, setting its kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if k.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+	return token.SignedString(k.signing.private)
+}
+
+// Keyfunc resolves the verification key for a token by its kid header.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if k.signing == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return k.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	verification, exists := k.verification[kid]
+	if !exists {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	if token.Method.Alg() != verification.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return verification.public, nil
+}
+
+// JWKS returns every verification key in JWK format. It is empty when tokens
+// are HMAC-signed, since the shared secret must never be published.
+func (k *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, verification := range k.verification {
+		jwk := publicJWK(verification.public)
+		jwk.KeyID = verification.id
+		jwk.Use = "sig"
+		jwk.Algorithm = verification.method.Alg()
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID })
+	return jwks
+}
+
+func loadPrivateKey(path string) (*key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type in %s", path)
+	}
+
+	result, err := newKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	result.private = signer
+	return result, nil
+}
+
+func loadPublicKey(path string) (*key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+
+	result, err := newKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
+
+func newKey(public crypto.PublicKey) (*key, error) {
+	result := &key{public: public}
+
+	switch public.(type) {
+	case ed25519.PublicKey:
+		result.method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		result.method = jwt.SigningMethodRS256
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (use Ed25519 or RSA)", public)
+	}
+
+	result.id = thumbprint(publicJWK(public))
+	return result, nil
+}
+
+func publicJWK(public crypto.PublicKey) JWK {
+	switch pub := public.(type) {
+	case ed25519.PublicKey:
+		return JWK{KeyType: "OKP", Curve: "Ed25519", X: base64URL(pub)}
+	case *rsa.PublicKey:
+		return JWK{KeyType: "RSA", N: base64URL(pub.N.Bytes()), E: base64URL(big.NewInt(int64(pub.E)).Bytes())}
+	}
+	return JWK{}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint, used as the key ID.
+func thumbprint(jwk JWK) string {
+	var members []string
+	if jwk.KeyType == "OKP" {
+		members = []string{`"crv":` + quote(jwk.Curve), `"kty":` + quote(jwk.KeyType), `"x":` + quote(jwk.X)}
+	} else {
+		members = []string{`"e":` + quote(jwk.E), `"kty":` + quote(jwk.KeyType), `"n":` + quote(jwk.N)}
+	}
+
+	sum := sha256.Sum256([]byte("{" + strings.Join(members, ",") + "}"))
+	return base64URL(sum[:])
+}
+
+func quote(value string) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func base64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
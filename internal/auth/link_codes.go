@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// Link codes are typed by hand, so they avoid characters that are easy to
+// confuse (0/O, 1/I/L).
+const linkCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+const linkCodeLength = 8
+
+// GenerateLinkCode returns a random code formatted as XXXX-XXXX. Codes are
+// stored hashed with HashOpaqueToken(NormalizeLinkCode(code)).
+func GenerateLinkCode() (string, error) {
+	var code strings.Builder
+	max := big.NewInt(int64(len(linkCodeAlphabet)))
+	for i := 0; i < linkCodeLength; i++ {
+		if i == linkCodeLength/2 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(linkCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// NormalizeLinkCode uppercases a code and strips separators so that
+// "abcd efgh" and "ABCD-EFGH" are the same code.
+func NormalizeLinkCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Headers carrying a signed request from a Minecraft server.
+const (
+	SignatureHeader = "X-PF-Signature"
+	TimestampHeader = "X-PF-Timestamp"
+	NonceHeader     = "X-PF-Nonce"
+)
+
+// ErrSigningSecretKey is returned when the key sealing signing secrets is
+// missing or is not 32 hex-encoded bytes.
+var ErrSigningSecretKey = errors.New("REQUEST_SIGNING_SECRET_KEY must be 64 hex characters")
+
+// GenerateSigningSecret returns a new random request signing secret. Like a
+// server key it is shown to the operator once; mods use the secret string
+// itself as the HMAC key.
+func GenerateSigningSecret() (string, error) {
+	return GenerateOpaqueToken(32)
+}
+
+// SealSigningSecret encrypts a server's signing secret for storage with
+// AES-256-GCM under the hex secretKey. The server ID is bound as additional
+// data, so a sealed secret copied onto another server's row will not open.
+func SealSigningSecret(secretKey, serverID, secret string) (string, error) {
+	aead, err := signingSecretCipher(secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), []byte(serverID))), nil
+}
+
+// OpenSigningSecret decrypts a secret sealed by SealSigningSecret and returns
+// the HMAC key it holds.
+func OpenSigningSecret(secretKey, serverID, sealed string) ([]byte, error) {
+	aead, err := signingSecretCipher(secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := hex.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, errors.New("malformed signing secret")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(serverID))
+}
+
+func signingSecretCipher(secretKey string) (cipher.AEAD, error) {
+	key, err := hex.DecodeString(secretKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrSigningSecretKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// CanonicalRequest builds the string that is signed: the method, the path
+// including any query string, the hex SHA-256 of the body, the Unix timestamp
+// and the nonce, separated by newlines.
+func CanonicalRequest(method, path string, body []byte, timestamp, nonce string) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		hex.EncodeToString(bodyHash[:]),
+		timestamp,
+		nonce,
+	}, "\n")
+}
+
+// SignRequest returns the hex HMAC-SHA256 of a canonical request.
+func SignRequest(key []byte, canonical string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequestSignature checks a hex signature in constant time.
+func VerifyRequestSignature(key []byte, canonical, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(canonical))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVerifyRequestSignature(t *testing.T) {
+	key := []byte("signing-secret")
+	body := []byte(`{"player_uuid":"069a79f4-44e9-4726-a5be-fca90e38aaf5"}`)
+	signed := CanonicalRequest("POST", "/api/v1/server/pokedex/update?sync=1", body, "1700000000", "nonce-1")
+	signature := SignRequest(key, signed)
+
+	tests := []struct {
+		name      string
+		key       []byte
+		canonical string
+		signature string
+		want      bool
+	}{
+		{"valid", key, signed, signature, true},
+		{"lowercase method", key, CanonicalRequest("post", "/api/v1/server/pokedex/update?sync=1", body, "1700000000", "nonce-1"), signature, true},
+		{"tampered body", key, CanonicalRequest("POST", "/api/v1/server/pokedex/update?sync=1", []byte(`{"player_uuid":"x"}`), "1700000000", "nonce-1"), signature, false},
+		{"tampered path", key, CanonicalRequest("POST", "/api/v1/server/player/update?sync=1", body, "1700000000", "nonce-1"), signature, false},
+		{"tampered query", key, CanonicalRequest("POST", "/api/v1/server/pokedex/update?sync=0", body, "1700000000", "nonce-1"), signature, false},
+		{"tampered method", key, CanonicalRequest("PUT", "/api/v1/server/pokedex/update?sync=1", body, "1700000000", "nonce-1"), signature, false},
+		{"tampered timestamp", key, CanonicalRequest("POST", "/api/v1/server/pokedex/update?sync=1", body, "1700000001", "nonce-1"), signature, false},
+		{"tampered nonce", key, CanonicalRequest("POST", "/api/v1/server/pokedex/update?sync=1", body, "1700000000", "nonce-2"), signature, false},
+		{"wrong key", []byte("other-secret"), signed, signature, false},
+		{"not hex", key, signed, "zz" + signature[2:], false},
+		{"truncated", key, signed, signature[:len(signature)-2], false},
+		{"empty", key, signed, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyRequestSignature(tt.key, tt.canonical, tt.signature); got != tt.want {
+				t.Errorf("VerifyRequestSignature() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalRequest(t *testing.T) {
+	got := CanonicalRequest("get", "/api/v1/server/player/abc?full=true", nil, "1700000000", "n1")
+	want := "GET\n/api/v1/server/player/abc?full=true\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855\n" +
+		"1700000000\nn1"
+	if got != want {
+		t.Errorf("CanonicalRequest() = %q, want %q", got, want)
+	}
+}
+
+func TestSigningSecretSealing(t *testing.T) {
+	secretKey := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	sealed, err := SealSigningSecret(secretKey, "survival_1", "secret-value")
+	if err != nil {
+		t.Fatalf("SealSigningSecret() error = %v", err)
+	}
+	if bytes.Contains([]byte(sealed), []byte("secret-value")) {
+		t.Fatalf("sealed secret contains the plain secret")
+	}
+
+	key, err := OpenSigningSecret(secretKey, "survival_1", sealed)
+	if err != nil || string(key) != "secret-value" {
+		t.Fatalf("OpenSigningSecret() = %q, %v, want secret-value", key, err)
+	}
+
+	if _, err := OpenSigningSecret(secretKey, "creative_1", sealed); err == nil {
+		t.Error("OpenSigningSecret() opened a secret sealed for another server")
+	}
+	otherKey := "1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"
+	if _, err := OpenSigningSecret(otherKey, "survival_1", sealed); err == nil {
+		t.Error("OpenSigningSecret() opened a secret with the wrong key")
+	}
+	if _, err := SealSigningSecret("", "survival_1", "secret-value"); err != ErrSigningSecretKey {
+		t.Errorf("SealSigningSecret() with no key error = %v, want ErrSigningSecretKey", err)
+	}
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"strings"
+)
+
+// Scopes carried in the "scope" claim of access tokens.
+const (
+	ScopePlayerRead   = "player:read"
+	ScopePlayerWrite  = "player:write"
+	ScopePokedexRead  = "pokedex:read"
+	ScopePokedexWrite = "pokedex:write"
+	ScopeWebAccount   = "web:account" // Web dashboard sessions: the player's own account pages
+	ScopeAdmin        = "admin"       // Satisfies every scope requirement
+)
+
+// Player roles. Admins get the admin scope on top of the regular player scopes.
+const (
+	RolePlayer = "player"
+	RoleAdmin  = "admin"
+)
+
+var knownScopes = map[string]bool{
+	ScopePlayerRead:   true,
+	ScopePlayerWrite:  true,
+	ScopePokedexRead:  true,
+	ScopePokedexWrite: true,
+	ScopeWebAccount:   true,
+	ScopeAdmin:        true,
+}
+
+// DefaultScopes are granted to player tokens and to newly registered servers.
+var DefaultScopes = []string{ScopePlayerRead, ScopePlayerWrite, ScopePokedexRead, ScopePokedexWrite}
+
+// IsKnownRole reports whether role is a valid player role.
+func IsKnownRole(role string) bool {
+	return role == RolePlayer || role == RoleAdmin
+}
+
+// IsKnownScope reports whether scope is one the API understands.
+func IsKnownScope(scope string) bool {
+	return knownScopes[scope]
+}
+
+// ParseScopes splits a space-separated scope string (the format used in the
+// token claim and in the database).
+func ParseScopes(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// FormatScopes joins scopes into a space-separated scope string.
+func FormatScopes(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// HasScopes reports whether granted covers every required scope.
+func HasScopes(granted []string, required ...string) bool {
+	grantedSet := map[string]bool{}
+	for _, scope := range granted {
+		grantedSet[scope] = true
+	}
+	if grantedSet[ScopeAdmin] {
+		return true
+	}
+
+	for _, scope := range required {
+		if !grantedSet[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// LimitScopes returns the scopes in requested that are covered by granted.
+func LimitScopes(requested, granted []string) []string {
+	limited := []string{}
+	for _, scope := range requested {
+		if HasScopes(granted, scope) {
+			limited = append(limited, scope)
+		}
+	}
+	return limited
+}
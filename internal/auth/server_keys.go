@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"crypto/subtle"
+)
+
+// GenerateServerKey returns a new random server key. The plain key is only
+// ever shown once to the operator; the database stores HashServerKey(key).
+func GenerateServerKey() (string, error) {
+	return GenerateOpaqueToken(32)
+}
+
+// HashServerKey hashes a server key for storage. Keys are 256-bit random
+// values, so a plain SHA-256 is sufficient (no salt or stretching needed).
+func HashServerKey(key string) string {
+	return HashOpaqueToken(key)
+}
+
+// VerifyServerKey compares a presented key against a stored hash in constant time.
+func VerifyServerKey(key, keyHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashServerKey(key)), []byte(keyHash)) == 1
+}
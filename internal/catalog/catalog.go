@@ -0,0 +1,407 @@
+// Package catalog holds the species and region data the Pokédex is built on.
+// The data is embedded in the binary and versioned, so completion math and
+// the /pokemon lookup API always agree with each other.
+package catalog
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//go:embed data/species.csv data/regions.json data/forms.csv
+var data embed.FS
+
+// Types every species' types must come from.
+var knownTypes = map[string]bool{
+	"normal": true, "fire": true, "water": true, "grass": true, "electric": true, "ice": true,
+	"fighting": true, "poison": true, "ground": true, "flying": true, "psychic": true, "bug": true,
+	"rock": true, "ghost": true, "dragon": true, "dark": true, "steel": true, "fairy": true,
+}
+
+// Species is one national Pokédex entry.
+type Species struct {
+	NationalID int          `json:"national_id"`
+	Name       string       `json:"name"`
+	Generation int          `json:"generation"`
+	Types      []string     `json:"types"`
+	Regions    []Membership `json:"regions"` // Every regional Pokédex listing the species
+	Forms      []Form       `json:"forms,omitempty"`
+}
+
+// Kinds of alternate form.
+const (
+	FormKindRegional = "regional" // Alolan, Galarian, Hisuian and Paldean forms
+	FormKindMega     = "mega"
+	FormKindPrimal   = "primal"
+	FormKindGender   = "gender" // Only where the genders are distinct forms
+	FormKindVariant  = "variant"
+)
+
+var knownFormKinds = map[string]bool{
+	FormKindRegional: true,
+	FormKindMega:     true,
+	FormKindPrimal:   true,
+	FormKindGender:   true,
+	FormKindVariant:  true,
+}
+
+// Form is an alternate form of a species. Every species also has its default
+// form, identified by an empty form ID, which is not listed.
+type Form struct {
+	ID   string `json:"form"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// Membership places a species in a regional Pokédex under its regional number.
+type Membership struct {
+	Region     string `json:"region"`
+	RegionalID int    `json:"regional_id"`
+}
+
+// Form returns the species' form with the given ID; "" is the default form.
+func (sp Species) Form(id string) (Form, bool) {
+	if id == "" {
+		return Form{Name: sp.Name}, true
+	}
+	for _, form := range sp.Forms {
+		if form.ID == id {
+			return form, true
+		}
+	}
+	return Form{}, false
+}
+
+// HasType reports whether the species has the given type.
+func (sp Species) HasType(typ string) bool {
+	for _, t := range sp.Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionalID returns the species' number in the named regional Pokédex.
+func (sp Species) RegionalID(region string) (int, bool) {
+	for _, m := range sp.Regions {
+		if m.Region == region {
+			return m.RegionalID, true
+		}
+	}
+	return 0, false
+}
+
+// Region is a regional Pokédex as numbered in the games. Entries holds the
+// national IDs in regional order, so regional number n is Entries[n-1]. A
+// species can appear in any number of regions.
+type Region struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Generation  int    `json:"generation"`
+	Size        int    `json:"size"`
+	Entries     []int  `json:"-"`
+}
+
+// Catalog is the loaded species and region data. It is read-only after Load
+// and safe for concurrent use.
+type Catalog struct {
+	version  string
+	species  []Species // Indexed by national ID - 1
+	forms    int       // Alternate forms across all species
+	byName   map[string]int
+	regions  []Region
+	byRegion map[string]int
+}
+
+// A region lists its national IDs in order with "entries", or with
+// "national_range" when the regional dex is a contiguous national range.
+type regionsFile struct {
+	Version string `json:"version"`
+	Regions []struct {
+		Name          string `json:"name"`
+		DisplayName   string `json:"display_name"`
+		Generation    int    `json:"generation"`
+		Entries       []int  `json:"entries"`
+		NationalRange []int  `json:"national_range"`
+	} `json:"regions"`
+}
+
+// Load parses and validates the embedded catalog.
+func Load() (*Catalog, error) {
+	c := &Catalog{
+		byName:   map[string]int{},
+		byRegion: map[string]int{},
+	}
+
+	if err := c.loadSpecies(); err != nil {
+		return nil, err
+	}
+	if err := c.loadRegions(); err != nil {
+		return nil, err
+	}
+	if err := c.loadForms(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Catalog) loadSpecies() error {
+	raw, err := data.ReadFile("data/species.csv")
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	if _, err := reader.Read(); err != nil { // Header
+		return fmt.Errorf("species.csv: %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("species.csv: %w", err)
+		}
+		if len(record) != 5 {
+			return fmt.Errorf("species.csv: expected 5 columns, got %d", len(record))
+		}
+
+		nationalID, err := strconv.Atoi(record[0])
+		if err != nil || nationalID != len(c.species)+1 {
+			return fmt.Errorf("species.csv: national IDs must be sequential from 1, got %q", record[0])
+		}
+		generation, err := strconv.Atoi(record[2])
+		if err != nil || generation < 1 {
+			return fmt.Errorf("species.csv: invalid generation for #%d", nationalID)
+		}
+
+		species := Species{
+			NationalID: nationalID,
+			Name:       record[1],
+			Generation: generation,
+			Regions:    []Membership{},
+		}
+		for _, typ := range record[3:5] {
+			if typ == "" {
+				continue
+			}
+			if !knownTypes[typ] {
+				return fmt.Errorf("species.csv: unknown type %q for #%d", typ, nationalID)
+			}
+			species.Types = append(species.Types, typ)
+		}
+		if len(species.Types) == 0 {
+			return fmt.Errorf("species.csv: #%d has no types", nationalID)
+		}
+
+		key := NormalizeName(species.Name)
+		if _, exists := c.byName[key]; exists {
+			return fmt.Errorf("species.csv: duplicate name %q", species.Name)
+		}
+		c.byName[key] = nationalID
+
+		c.species = append(c.species, species)
+	}
+
+	if len(c.species) == 0 {
+		return fmt.Errorf("species.csv: no species")
+	}
+	return nil
+}
+
+func (c *Catalog) loadRegions() error {
+	raw, err := data.ReadFile("data/regions.json")
+	if err != nil {
+		return err
+	}
+
+	var file regionsFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return fmt.Errorf("regions.json: %w", err)
+	}
+	if file.Version == "" {
+		return fmt.Errorf("regions.json: missing version")
+	}
+	c.version = file.Version
+
+	for _, def := range file.Regions {
+		if _, exists := c.byRegion[def.Name]; exists {
+			return fmt.Errorf("regions.json: duplicate region %s", def.Name)
+		}
+
+		entries := def.Entries
+		if len(def.NationalRange) > 0 {
+			if len(entries) > 0 || len(def.NationalRange) != 2 || def.NationalRange[0] > def.NationalRange[1] {
+				return fmt.Errorf("regions.json: %s needs either entries or a [first, last] national_range", def.Name)
+			}
+			for id := def.NationalRange[0]; id <= def.NationalRange[1]; id++ {
+				entries = append(entries, id)
+			}
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("regions.json: %s has no entries", def.Name)
+		}
+
+		for i, id := range entries {
+			if id < 1 || id > len(c.species) {
+				return fmt.Errorf("regions.json: %s lists unknown national ID %d", def.Name, id)
+			}
+			species := &c.species[id-1]
+			if _, listed := species.RegionalID(def.Name); listed {
+				return fmt.Errorf("regions.json: %s lists #%d twice", def.Name, id)
+			}
+			species.Regions = append(species.Regions, Membership{Region: def.Name, RegionalID: i + 1})
+		}
+
+		c.byRegion[def.Name] = len(c.regions)
+		c.regions = append(c.regions, Region{
+			Name:        def.Name,
+			DisplayName: def.DisplayName,
+			Generation:  def.Generation,
+			Size:        len(entries),
+			Entries:     entries,
+		})
+	}
+
+	return nil
+}
+
+func (c *Catalog) loadForms() error {
+	raw, err := data.ReadFile("data/forms.csv")
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	if _, err := reader.Read(); err != nil { // Header
+		return fmt.Errorf("forms.csv: %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("forms.csv: %w", err)
+		}
+		if len(record) != 4 {
+			return fmt.Errorf("forms.csv: expected 4 columns, got %d", len(record))
+		}
+
+		nationalID, err := strconv.Atoi(record[0])
+		if err != nil || nationalID < 1 || nationalID > len(c.species) {
+			return fmt.Errorf("forms.csv: unknown national ID %q", record[0])
+		}
+		form := Form{ID: record[1], Name: record[2], Kind: record[3]}
+		if form.ID == "" || form.ID != strings.ToLower(form.ID) {
+			return fmt.Errorf("forms.csv: invalid form ID %q for #%d", form.ID, nationalID)
+		}
+		if !knownFormKinds[form.Kind] {
+			return fmt.Errorf("forms.csv: unknown kind %q for #%d %s", form.Kind, nationalID, form.ID)
+		}
+
+		species := &c.species[nationalID-1]
+		if _, exists := species.Form(form.ID); exists {
+			return fmt.Errorf("forms.csv: duplicate form #%d %s", nationalID, form.ID)
+		}
+		species.Forms = append(species.Forms, form)
+		c.forms++
+	}
+
+	return nil
+}
+
+// NormalizeName folds a species name for lookups, so "Mr. Mime", "mr-mime"
+// and "MrMime" all match, and "nidoran-f" matches "Nidoran♀".
+func NormalizeName(name string) string {
+	name = strings.NewReplacer("♀", "f", "♂", "m", "é", "e", "É", "e").Replace(name)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, name)
+}
+
+// Version identifies the catalog data so clients can cache lookups.
+func (c *Catalog) Version() string {
+	return c.version
+}
+
+// Total is the number of species in the national Pokédex.
+func (c *Catalog) Total() int {
+	return len(c.species)
+}
+
+// TotalForms is the size of the forms dex: every species' default form plus
+// each alternate form.
+func (c *Catalog) TotalForms() int {
+	return len(c.species) + c.forms
+}
+
+// Species returns the species with the given national ID.
+func (c *Catalog) Species(nationalID int) (Species, bool) {
+	if nationalID < 1 || nationalID > len(c.species) {
+		return Species{}, false
+	}
+	return c.species[nationalID-1], true
+}
+
+// Lookup finds a species by national ID or by name.
+func (c *Catalog) Lookup(idOrName string) (Species, bool) {
+	if nationalID, err := strconv.Atoi(idOrName); err == nil {
+		return c.Species(nationalID)
+	}
+	nationalID, exists := c.byName[NormalizeName(idOrName)]
+	if !exists {
+		return Species{}, false
+	}
+	return c.species[nationalID-1], true
+}
+
+// All returns every species in national order.
+func (c *Catalog) All() []Species {
+	return c.species
+}
+
+// Regions returns the regional Pokédexes in catalog order.
+func (c *Catalog) Regions() []Region {
+	return c.regions
+}
+
+// Region returns the named regional Pokédex.
+func (c *Catalog) Region(name string) (Region, bool) {
+	index, exists := c.byRegion[name]
+	if !exists {
+		return Region{}, false
+	}
+	return c.regions[index], true
+}
+
+// NationalID returns the national ID listed under a regional number.
+func (c *Catalog) NationalID(region string, regionalID int) (int, bool) {
+	r, exists := c.Region(region)
+	if !exists || regionalID < 1 || regionalID > r.Size {
+		return 0, false
+	}
+	return r.Entries[regionalID-1], true
+}
+
+// IsKnownType reports whether typ is one of the eighteen types.
+func IsKnownType(typ string) bool {
+	return knownTypes[typ]
+}
@@ -0,0 +1,186 @@
+package catalog
+
+import "testing"
+
+func loadCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return c
+}
+
+func TestLoad(t *testing.T) {
+	c := loadCatalog(t)
+
+	if got := c.Total(); got != 1025 {
+		t.Errorf("Total() = %d, want 1025", got)
+	}
+	if got := c.TotalForms(); got != 1188 {
+		t.Errorf("TotalForms() = %d, want 1188", got)
+	}
+	if c.Version() == "" {
+		t.Error("Version() is empty")
+	}
+
+	for i, sp := range c.All() {
+		if sp.NationalID != i+1 {
+			t.Fatalf("All()[%d] has national ID %d", i, sp.NationalID)
+		}
+		if len(sp.Regions) == 0 {
+			t.Errorf("%s (#%d) is in no regional Pokédex", sp.Name, sp.NationalID)
+		}
+	}
+}
+
+func TestRegions(t *testing.T) {
+	c := loadCatalog(t)
+
+	sizes := map[string]int{
+		"kanto":  153,
+		"johto":  256,
+		"hoenn":  211,
+		"sinnoh": 210,
+		"unova":  301,
+		"kalos":  457,
+		"alola":  307,
+		"galar":  408,
+		"hisui":  242,
+		"paldea": 417,
+	}
+	if len(c.Regions()) != len(sizes) {
+		t.Errorf("len(Regions()) = %d, want %d", len(c.Regions()), len(sizes))
+	}
+	for name, size := range sizes {
+		r, ok := c.Region(name)
+		if !ok {
+			t.Errorf("Region(%q) not found", name)
+			continue
+		}
+		if r.Size != size || len(r.Entries) != size {
+			t.Errorf("Region(%q) size = %d with %d entries, want %d", name, r.Size, len(r.Entries), size)
+		}
+	}
+
+	if _, ok := c.Region("orre"); ok {
+		t.Error(`Region("orre") found`)
+	}
+}
+
+func TestMemberships(t *testing.T) {
+	c := loadCatalog(t)
+
+	tests := []struct {
+		nationalID int
+		region     string
+		regionalID int
+	}{
+		{25, "kanto", 25},
+		{25, "alola", 25},
+		{25, "galar", 194},
+		{25, "paldea", 74},
+		{1, "kanto", 1},
+		{808, "kanto", 152},
+		{152, "johto", 1},
+		{252, "hoenn", 1},
+		{387, "sinnoh", 1},
+		{495, "unova", 1},
+		{494, "unova", 301},
+		{650, "kalos", 1},
+		{722, "alola", 1},
+		{810, "galar", 1},
+		{906, "paldea", 1},
+	}
+
+	for _, tt := range tests {
+		sp, ok := c.Species(tt.nationalID)
+		if !ok {
+			t.Errorf("Species(%d) not found", tt.nationalID)
+			continue
+		}
+		if got, ok := sp.RegionalID(tt.region); !ok || got != tt.regionalID {
+			t.Errorf("%s RegionalID(%q) = %d, %t, want %d", sp.Name, tt.region, got, ok, tt.regionalID)
+		}
+		if got, ok := c.NationalID(tt.region, tt.regionalID); !ok || got != tt.nationalID {
+			t.Errorf("NationalID(%q, %d) = %d, %t, want %d", tt.region, tt.regionalID, got, ok, tt.nationalID)
+		}
+	}
+
+	pikachu, _ := c.Species(25)
+	if _, ok := pikachu.RegionalID("unova"); ok {
+		t.Error("Pikachu listed in unova")
+	}
+	if _, ok := c.NationalID("kanto", 154); ok {
+		t.Error(`NationalID("kanto", 154) found past the end of the region`)
+	}
+	if _, ok := c.NationalID("kanto", 0); ok {
+		t.Error(`NationalID("kanto", 0) found`)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pikachu", "pikachu"},
+		{"Mr. Mime", "mrmime"},
+		{"mr-mime", "mrmime"},
+		{"MrMime", "mrmime"},
+		{"Nidoran♀", "nidoranf"},
+		{"nidoran-f", "nidoranf"},
+		{"Nidoran♂", "nidoranm"},
+		{"Farfetch'd", "farfetchd"},
+		{"Flabébé", "flabebe"},
+		{"Type: Null", "typenull"},
+		{"Porygon-Z", "porygonz"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c := loadCatalog(t)
+
+	tests := []struct {
+		query string
+		want  int // 0 when not found
+	}{
+		{"25", 25},
+		{"pikachu", 25},
+		{"PIKACHU", 25},
+		{"Mr. Mime", 122},
+		{"mr-mime", 122},
+		{"mime jr", 439},
+		{"nidoran-f", 29},
+		{"nidoran-m", 32},
+		{"Nidoran♂", 32},
+		{"farfetchd", 83},
+		{"flabebe", 669},
+		{"type-null", 772},
+		{"1025", 1025},
+		{"0", 0},
+		{"1026", 0},
+		{"missingno", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		sp, ok := c.Lookup(tt.query)
+		if tt.want == 0 {
+			if ok {
+				t.Errorf("Lookup(%q) = %s, want not found", tt.query, sp.Name)
+			}
+			continue
+		}
+		if !ok || sp.NationalID != tt.want {
+			t.Errorf("Lookup(%q) = #%d, %t, want #%d", tt.query, sp.NationalID, ok, tt.want)
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package config
 
 import (
+	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Server    ServerConfig
+	RateLimit RateLimitConfig
+	Signing   SigningConfig
+	TLS       TLSConfig
+	Pokedex   PokedexConfig
 }
 
 type DatabaseConfig struct {
@@ -19,14 +28,62 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string
+	Secret               string
+	SigningKeyFile       string   // Ed25519 or RSA private key (PEM); empty means HS256 with Secret
+	VerificationKeyFiles []string // Extra public keys (PEM) still accepted during key rotation
 }
 
 type ServerConfig struct {
-	Port string
+	Host           string // Interface for the server/player API; empty means all, the default unless WebPort is set
+	Port           string
+	WebHost        string   // Interface for the public web dashboard
+	WebPort        string   // Separate web dashboard listener; empty serves /web on the API listener
+	TrustedProxies []string // Proxies whose X-Forwarded-For is trusted for client IPs
+}
+
+// RateLimitConfig holds token bucket rules as "<burst>/<period>" (e.g. "10/1m");
+// "off" disables a rule.
+type RateLimitConfig struct {
+	Store          string // "memory" or "postgres" (shared between instances)
+	Login          string
+	ServerAuth     string
+	AuthFailures   string
+	Web            string
+	ServerRequests string
+	PlayerRequests string
+}
+
+// TLSConfig enables HTTPS on the API listener. With a client CA, Minecraft
+// servers can authenticate with a certificate whose CN is their server_id.
+type TLSConfig struct {
+	CertFile     string // PEM certificate chain; empty serves plain HTTP
+	KeyFile      string // PEM private key
+	ClientCAFile string // PEM CA bundle used to verify client certificates
+	ClientAuth   string // "none", "optional" or "required"
+}
+
+// SigningConfig controls HMAC request signing on the server proxy routes.
+type SigningConfig struct {
+	Required  bool          // Require signatures from every server, not just those flagged in game_servers
+	MaxSkew   time.Duration // How far a request timestamp may be from the API clock
+	SecretKey string        // Hex AES-256 key sealing the per-server signing secrets stored in game_servers
+}
+
+// PokedexConfig holds the regional completion percentages recorded as
+// milestones. 100 is always included.
+type PokedexConfig struct {
+	Milestones []int
 }
 
 func Load() *Config {
+	// With a separate web listener the API is internal, so it only listens on
+	// localhost unless API_HOST says otherwise
+	webPort := getEnv("WEB_PORT", "")
+	apiHost := ""
+	if webPort != "" {
+		apiHost = "127.0.0.1"
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -36,10 +93,39 @@ func Load() *Config {
 			Password: getEnv("DB_PASSWORD", "password"),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-secret-key"),
+			Secret:               getEnv("JWT_SECRET", "your-secret-key"),
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: getEnvList("JWT_VERIFICATION_KEY_FILES"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("API_PORT", "8080"),
+			Host:           getEnv("API_HOST", apiHost),
+			Port:           getEnv("API_PORT", "8080"),
+			WebHost:        getEnv("WEB_HOST", ""),
+			WebPort:        webPort,
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+		},
+		RateLimit: RateLimitConfig{
+			Store:          getEnv("RATE_LIMIT_STORE", "memory"),
+			Login:          getEnv("RATE_LIMIT_LOGIN", "10/1m"),
+			ServerAuth:     getEnv("RATE_LIMIT_SERVER_AUTH", "10/1m"),
+			AuthFailures:   getEnv("RATE_LIMIT_AUTH_FAILURES", "5/15m"),
+			Web:            getEnv("RATE_LIMIT_WEB", "60/1m"),
+			ServerRequests: getEnv("RATE_LIMIT_SERVER", "1200/1m"),
+			PlayerRequests: getEnv("RATE_LIMIT_PLAYER", "120/1m"),
+		},
+		TLS: TLSConfig{
+			CertFile:     getEnv("TLS_CERT_FILE", ""),
+			KeyFile:      getEnv("TLS_KEY_FILE", ""),
+			ClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+			ClientAuth:   getEnv("TLS_CLIENT_AUTH", "none"),
+		},
+		Signing: SigningConfig{
+			Required:  getEnvBool("REQUEST_SIGNING_REQUIRED", false),
+			MaxSkew:   getEnvDuration("REQUEST_SIGNING_MAX_SKEW", 5*time.Minute),
+			SecretKey: getEnv("REQUEST_SIGNING_SECRET_KEY", ""),
+		},
+		Pokedex: PokedexConfig{
+			Milestones: getEnvPercentages("POKEDEX_MILESTONES", []int{25, 50, 75, 100}),
 		},
 	}
 }
@@ -49,4 +135,65 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvPercentages parses a comma-separated list of whole percentages from 1
+// to 100, returned sorted with 100 always present.
+func getEnvPercentages(key string, defaultValue []int) []int {
+	values := getEnvList(key)
+	if len(values) == 0 {
+		return defaultValue
+	}
+
+	seen := map[int]bool{100: true}
+	percentages := []int{100}
+	for _, value := range values {
+		parsed, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || parsed < 1 || parsed > 100 {
+			log.Printf("Invalid %s %q, using %v", key, os.Getenv(key), defaultValue)
+			return defaultValue
+		}
+		if !seen[parsed] {
+			seen[parsed] = true
+			percentages = append(percentages, parsed)
+		}
+	}
+	sort.Ints(percentages)
+	return percentages
+}
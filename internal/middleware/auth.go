@@ -4,11 +4,17 @@ import (
 	"net/http"
 	"strings"
 
+	"pokefactory_server/internal/auth"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// SessionValidator reports whether the session identified by a token's jti
+// claim is still active.
+type SessionValidator func(jti string) bool
+
+func AuthMiddleware(keyfunc jwt.Keyfunc, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -24,12 +30,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(jwtSecret), nil
-		})
+		token, err := jwt.Parse(tokenString, keyfunc)
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -37,9 +38,32 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("player_uuid", claims["uuid"])
-			c.Set("player_id", claims["player_id"])
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		// Reject tokens whose session has been revoked or replaced
+		jti, _ := claims["jti"].(string)
+		if jti == "" || !sessions(jti) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+			c.Abort()
+			return
+		}
+		c.Set("token_id", jti)
+
+		scope, _ := claims["scope"].(string)
+		c.Set("scopes", auth.ParseScopes(scope))
+
+		c.Set("player_uuid", claims["uuid"])
+		c.Set("player_id", claims["player_id"])
+
+		// Server tokens reaching player-token routes (e.g. admin) are
+		// identified by their server_id
+		if serverID, ok := claims["server_id"].(string); ok {
+			c.Set("server_id", serverID)
 		}
 
 		c.Next()
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pokefactory_server/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc derives the rate limit bucket key for a request. An empty key
+// means the request is not limited.
+type KeyFunc func(c *gin.Context) string
+
+// RateLimit throttles requests per key. Store errors fail open so an
+// unavailable store cannot take the API down with it.
+func RateLimit(store ratelimit.Store, rule ratelimit.Rule, keyFunc KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" || !rule.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(key, rule, 1)
+		if err != nil {
+			log.Printf("Rate limit store error for %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		if !result.Allowed {
+			TooManyRequests(c, result.RetryAfter, "Rate limit exceeded")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TooManyRequests aborts with 429 and a Retry-After header in whole seconds.
+func TooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": message, "retry_after": seconds})
+	c.Abort()
+}
+
+// ClientIPKey limits per client IP.
+func ClientIPKey(scope string) KeyFunc {
+	return func(c *gin.Context) string {
+		return scope + ":ip:" + c.ClientIP()
+	}
+}
+
+// ContextKey limits per value set on the context by an auth middleware,
+// e.g. "server_id" or "player_id".
+func ContextKey(scope, name string) KeyFunc {
+	return func(c *gin.Context) string {
+		value, exists := c.Get(name)
+		if !exists || value == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s:%s:%v", scope, name, value)
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pokefactory_server/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Longest nonce accepted; anything longer is not a nonce a mod would send.
+const maxNonceLength = 128
+
+// SigningPolicy returns a server's HMAC key and whether it must sign every
+// request. A nil key means the server has no signing secret.
+type SigningPolicy func(serverID string) (key []byte, required bool, err error)
+
+// NonceRecorder stores a server's nonce until expiresAt, reporting false if
+// the nonce has already been used.
+type NonceRecorder func(serverID, nonce string, expiresAt time.Time) (bool, error)
+
+// RequestSignature verifies HMAC-signed server requests. It must run after
+// ServerAuthMiddleware. Unsigned requests pass through unless the policy
+// requires signing; signed requests must carry a timestamp within maxSkew
+// of the API clock and a nonce that has not been seen before.
+func RequestSignature(policy SigningPolicy, nonces NonceRecorder, maxSkew time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serverID := c.GetString("server_id")
+
+		key, required, err := policy(serverID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid server credentials"})
+			c.Abort()
+			return
+		}
+
+		signature := c.GetHeader(auth.SignatureHeader)
+		if signature == "" {
+			if required {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Request signature required"})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// An empty key would make every signature forgeable
+		if len(key) == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Request signing is not set up for this server"})
+			c.Abort()
+			return
+		}
+
+		timestamp := c.GetHeader(auth.TimestampHeader)
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid request timestamp"})
+			c.Abort()
+			return
+		}
+		signedAt := time.Unix(unix, 0)
+		if skew := time.Since(signedAt); skew > maxSkew || skew < -maxSkew {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Request timestamp outside allowed window"})
+			c.Abort()
+			return
+		}
+
+		nonce := c.GetHeader(auth.NonceHeader)
+		if nonce == "" || len(nonce) > maxNonceLength {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid request nonce"})
+			c.Abort()
+			return
+		}
+
+		// Read the body for hashing and put it back for the handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		canonical := auth.CanonicalRequest(c.Request.Method, c.Request.URL.RequestURI(), body, timestamp, nonce)
+		if !auth.VerifyRequestSignature(key, canonical, signature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid request signature"})
+			c.Abort()
+			return
+		}
+
+		// A nonce only needs remembering while its timestamp is still accepted
+		fresh, err := nonces(serverID, nonce, signedAt.Add(maxSkew))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify request nonce"})
+			c.Abort()
+			return
+		}
+		if !fresh {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Request nonce already used"})
+			c.Abort()
+			return
+		}
+
+		c.Set("request_signed", true)
+		c.Next()
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pokefactory_server/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type signedRequest struct {
+	path      string
+	body      string
+	signBody  string // body covered by the signature; defaults to body
+	timestamp time.Time
+	nonce     string
+	unsigned  bool
+}
+
+func newSigningRouter(key []byte, required bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	used := map[string]bool{}
+	policy := func(serverID string) ([]byte, bool, error) {
+		return key, required, nil
+	}
+	nonces := func(serverID, nonce string, expiresAt time.Time) (bool, error) {
+		if used[serverID+"/"+nonce] {
+			return false, nil
+		}
+		used[serverID+"/"+nonce] = true
+		return true, nil
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("server_id", "survival_1") })
+	router.Use(RequestSignature(policy, nonces, 5*time.Minute))
+	router.POST("/server/pokedex", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func (r signedRequest) send(router *gin.Engine, key []byte) int {
+	req := httptest.NewRequest(http.MethodPost, r.path, strings.NewReader(r.body))
+	if !r.unsigned {
+		signBody := r.signBody
+		if signBody == "" {
+			signBody = r.body
+		}
+		timestamp := strconv.FormatInt(r.timestamp.Unix(), 10)
+		canonical := auth.CanonicalRequest(http.MethodPost, "/server/pokedex", []byte(signBody), timestamp, r.nonce)
+		req.Header.Set(auth.TimestampHeader, timestamp)
+		req.Header.Set(auth.NonceHeader, r.nonce)
+		req.Header.Set(auth.SignatureHeader, auth.SignRequest(key, canonical))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequestSignature(t *testing.T) {
+	key := []byte("signing-secret")
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		required bool
+		request  signedRequest
+		want     int
+	}{
+		{"valid", false, signedRequest{path: "/server/pokedex", body: `{"a":1}`, timestamp: now, nonce: "n1"}, http.StatusNoContent},
+		{"unsigned when optional", false, signedRequest{path: "/server/pokedex", unsigned: true}, http.StatusNoContent},
+		{"unsigned when required", true, signedRequest{path: "/server/pokedex", unsigned: true}, http.StatusUnauthorized},
+		{"tampered body", false, signedRequest{path: "/server/pokedex", body: `{"a":2}`, signBody: `{"a":1}`, timestamp: now, nonce: "n1"}, http.StatusUnauthorized},
+		{"tampered query", false, signedRequest{path: "/server/pokedex?a=1", timestamp: now, nonce: "n1"}, http.StatusUnauthorized},
+		{"timestamp too old", false, signedRequest{path: "/server/pokedex", timestamp: now.Add(-6 * time.Minute), nonce: "n1"}, http.StatusUnauthorized},
+		{"timestamp too new", false, signedRequest{path: "/server/pokedex", timestamp: now.Add(6 * time.Minute), nonce: "n1"}, http.StatusUnauthorized},
+		{"timestamp within skew", false, signedRequest{path: "/server/pokedex", timestamp: now.Add(-4 * time.Minute), nonce: "n1"}, http.StatusNoContent},
+		{"missing nonce", false, signedRequest{path: "/server/pokedex", timestamp: now}, http.StatusUnauthorized},
+		{"oversized nonce", false, signedRequest{path: "/server/pokedex", timestamp: now, nonce: strings.Repeat("n", maxNonceLength+1)}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newSigningRouter(key, tt.required)
+			if got := tt.request.send(router, key); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestSignatureRejectsReplayedNonce(t *testing.T) {
+	key := []byte("signing-secret")
+	router := newSigningRouter(key, false)
+	request := signedRequest{path: "/server/pokedex", body: `{"a":1}`, timestamp: time.Now(), nonce: "n1"}
+
+	if got := request.send(router, key); got != http.StatusNoContent {
+		t.Fatalf("first request status = %d, want %d", got, http.StatusNoContent)
+	}
+	if got := request.send(router, key); got != http.StatusUnauthorized {
+		t.Errorf("replayed request status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestRequestSignatureRequiresKey(t *testing.T) {
+	// With no signing secret an empty-key HMAC must not be accepted
+	router := newSigningRouter(nil, false)
+	request := signedRequest{path: "/server/pokedex", timestamp: time.Now(), nonce: "n1"}
+
+	if got := request.send(router, nil); got != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pokefactory_server/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScopes rejects requests whose token lacks any of the given scopes.
+// It must run after AuthMiddleware or ServerAuthMiddleware.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, _ := c.Get("scopes")
+		grantedScopes, _ := granted.([]string)
+
+		if !auth.HasScopes(grantedScopes, scopes...) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient scope", "required_scopes": scopes})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
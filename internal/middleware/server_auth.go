@@ -4,11 +4,13 @@ import (
 	"net/http"
 	"strings"
 
+	"pokefactory_server/internal/auth"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func ServerAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+func ServerAuthMiddleware(keyfunc jwt.Keyfunc, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -24,12 +26,7 @@ func ServerAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(jwtSecret), nil
-		})
+		token, err := jwt.Parse(tokenString, keyfunc)
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -37,14 +34,38 @@ func ServerAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			// Verify this is a server token
-			if tokenType, exists := claims["type"]; !exists || tokenType != "server" {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token type"})
-				c.Abort()
-				return
-			}
-			c.Set("server_id", claims["server_id"])
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		// Reject tokens whose session has been revoked or replaced
+		jti, _ := claims["jti"].(string)
+		if jti == "" || !sessions(jti) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+			c.Abort()
+			return
+		}
+		c.Set("token_id", jti)
+
+		scope, _ := claims["scope"].(string)
+		c.Set("scopes", auth.ParseScopes(scope))
+
+		// Verify this is a server token
+		if tokenType, exists := claims["type"]; !exists || tokenType != "server" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token type"})
+			c.Abort()
+			return
+		}
+		c.Set("server_id", claims["server_id"])
+
+		// A client certificate, when presented, must belong to the same server
+		if certServerID := ClientCertServerID(c); certServerID != "" && certServerID != c.GetString("server_id") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Client certificate does not match token"})
+			c.Abort()
+			return
 		}
 
 		c.Next()
@@ -0,0 +1,28 @@
+package models
+
+// Admin API request/response models for moderation and maintenance
+type AdminPlayerDetail struct {
+	Player  Player          `json:"player"`
+	Stats   *PlayerStats    `json:"stats"`
+	Pokedex *PokedexSummary `json:"pokedex"`
+	Data    []PlayerData    `json:"data"`
+}
+
+type AdminRenameRequest struct {
+	Username string `json:"username" binding:"required,max=16"`
+}
+
+type AdminRoleRequest struct {
+	Role string `json:"role" binding:"required"` // "player" or "admin"
+}
+
+type AdminPokedexUpdateRequest struct {
+	NationalID int    `json:"national_id" binding:"required"`
+	Action     string `json:"action" binding:"required,oneof=catch see uncatch unsee"`
+	Form       string `json:"form,omitempty"`
+	Shiny      bool   `json:"shiny,omitempty"`
+}
+
+type AdminPokedexResetRequest struct {
+	Region string `json:"region,omitempty"` // Empty resets every region
+}
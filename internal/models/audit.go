@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLogEntry struct {
+	ID        int64           `json:"id" db:"id"`
+	ActorType string          `json:"actor_type" db:"actor_type"` // "server" or "player"
+	ActorID   string          `json:"actor_id" db:"actor_id"`     // server_id or player ID
+	PlayerID  *int            `json:"player_id" db:"player_id"`   // Player whose data changed
+	Action    string          `json:"action" db:"action"`
+	Route     string          `json:"route" db:"route"`
+	Before    json.RawMessage `json:"before" db:"before_value"`
+	After     json.RawMessage `json:"after" db:"after_value"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type AuditLogFilter struct {
+	PlayerID  int
+	ActorType string
+	ActorID   string
+	Since     *time.Time
+	Until     *time.Time
+	Limit     int
+	Offset    int
+}
+
+// PokedexEntryState is the audited view of one species' Pokédex entry across
+// every regional Pokédex listing it.
+type PokedexEntryState struct {
+	NationalID int    `json:"national_id"`
+	Form       string `json:"form,omitempty"`
+	Shiny      bool   `json:"shiny,omitempty"`
+	Caught     bool   `json:"caught"`
+	Seen       bool   `json:"seen"`
+}
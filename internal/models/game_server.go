@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+type GameServer struct {
+	ID             int        `json:"id" db:"id"`
+	ServerID       string     `json:"server_id" db:"server_id"`
+	KeyHash        string     `json:"-" db:"key_hash"`
+	Enabled        bool       `json:"enabled" db:"enabled"`
+	Scopes         string     `json:"scopes" db:"scopes"`                   // Space-separated scopes granted to this server's tokens
+	RequireSigning bool       `json:"require_signing" db:"require_signing"` // Reject unsigned requests from this server
+	SigningSecret  *string    `json:"-" db:"signing_secret_encrypted"`      // Sealed HMAC key; nil until one is issued
+	LastAuthAt     *time.Time `json:"last_auth_at" db:"last_auth_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -8,6 +8,8 @@ type Player struct {
 	ID           int       `json:"id" db:"id"`
 	UUID         string    `json:"uuid" db:"uuid"`
 	Username     string    `json:"username" db:"username"`
+	Role         string    `json:"role,omitempty" db:"role"`       // "player" or "admin"
+	Privacy      string    `json:"privacy,omitempty" db:"privacy"` // See Privacy* constants
 	LastLogin    time.Time `json:"last_login" db:"last_login"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
@@ -31,4 +33,19 @@ type PlayerData struct {
 	DataValue    string    `json:"data_value" db:"data_value"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+}
+// Privacy settings controlling what the public web dashboard shows.
+const (
+	PrivacyPublic   = "public"   // Listed on leaderboards and visible by username
+	PrivacyUnlisted = "unlisted" // Hidden from leaderboards, still visible by username
+	PrivacyPrivate  = "private"  // Hidden from every public web endpoint
+)
+
+// IsKnownPrivacy reports whether privacy is a valid privacy setting.
+func IsKnownPrivacy(privacy string) bool {
+	return privacy == PrivacyPublic || privacy == PrivacyUnlisted || privacy == PrivacyPrivate
+}
+
+type PrivacyRequest struct {
+	Privacy string `json:"privacy" binding:"required"`
+}
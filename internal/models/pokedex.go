@@ -11,13 +11,22 @@ type PokedexSummary struct {
 	TotalSeen                 int       `json:"total_seen" db:"total_seen"`     //This is a total based on national dex
 	RegionsCompleted          int       `json:"regions_completed" db:"regions_completed"`
 	NationalCompletionPercent float64   `json:"national_completion_percentage" db:"national_completion_percentage"`
+	FormsCaught               int       `json:"forms_caught" db:"forms_caught"`
+	FormsCompletionPercent    float64   `json:"forms_completion_percentage" db:"forms_completion_percentage"`
+	ShinyCaught               int       `json:"shiny_caught" db:"shiny_caught"` //Species caught shiny in any form
+	ShinyCompletionPercent    float64   `json:"shiny_completion_percentage" db:"shiny_completion_percentage"`
+	LivingOwned               int       `json:"living_owned" db:"living_owned"` //Species currently owned (party + PC)
+	LivingCompletionPercent   float64   `json:"living_completion_percentage" db:"living_completion_percentage"`
 	LastUpdated               time.Time `json:"last_updated" db:"last_updated"`
 	CreatedAt                 time.Time `json:"created_at" db:"created_at"`
 }
 
+// RegionalPokedex is a player's progress in one region. The flags are built
+// from the player's species entries in regional order.
 type RegionalPokedex struct {
 	ID                   int        `json:"id" db:"id"`
 	PlayerID             int        `json:"player_id" db:"player_id"`
+	Region               string     `json:"region" db:"region"`
 	CaughtFlags          []byte     `json:"caught_flags" db:"caught_flags"` //Returns as base64-encoded string (storage artifacts: not used for display)
 	SeenFlags            []byte     `json:"seen_flags" db:"seen_flags"`     //Returns as base64-encoded string (storage artifacts: not used for display)
 	CompletionDate       *time.Time `json:"completion_date" db:"completion_date"`
@@ -26,11 +35,118 @@ type RegionalPokedex struct {
 	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// DecodedRegionalPokedex is a regional Pokédex with its flags resolved to
+// species, each list in regional order. Missing lists the species not yet
+// caught.
+type DecodedRegionalPokedex struct {
+	Region               string              `json:"region"`
+	DisplayName          string              `json:"display_name"`
+	Size                 int                 `json:"size"`
+	CaughtCount          int                 `json:"caught_count"`
+	SeenCount            int                 `json:"seen_count"`
+	MissingCount         int                 `json:"missing_count"`
+	CompletionPercentage float64             `json:"completion_percentage"`
+	CompletionDate       *time.Time          `json:"completion_date"`
+	Caught               []PokedexSpeciesRef `json:"caught"`
+	Seen                 []PokedexSpeciesRef `json:"seen"`
+	Missing              []PokedexSpeciesRef `json:"missing"`
+	UpdatedAt            time.Time           `json:"updated_at"`
+}
+
+// PokedexSpeciesRef names a species by its national and regional numbers.
+type PokedexSpeciesRef struct {
+	NationalID int    `json:"national_id"`
+	RegionalID int    `json:"regional_id"`
+	Name       string `json:"name"`
+}
+
 type PokedexUpdateRequest struct {
-	Region     string `json:"region,omitempty"`              // Optional - will be auto-determined if not provided
-	PokemonID  int    `json:"pokemon_id" binding:"required"` // Can be national or regional ID
-	NationalID int    `json:"national_id,omitempty"`         // Optional - use this for national dex numbers
-	Action     string `json:"action" binding:"required"`     // "catch" or "see"
+	Region     string          `json:"region,omitempty"`                          // Optional - will be auto-determined if not provided
+	PokemonID  int             `json:"pokemon_id" binding:"required"`             // Can be national or regional ID
+	NationalID int             `json:"national_id,omitempty"`                     // Optional - use this for national dex numbers
+	Action     string          `json:"action" binding:"required,oneof=catch see"` // "catch" or "see"
+	Form       string          `json:"form,omitempty"`                            // Optional - alternate form ID, e.g. "alola" or "mega-x"
+	Shiny      bool            `json:"shiny,omitempty"`
+	Capture    *PokedexCapture `json:"capture,omitempty"`     // Optional - where and how it was caught
+	OccurredAt *time.Time      `json:"occurred_at,omitempty"` // Optional - defaults to now; set for queued events
+}
+
+// PokedexCapture describes the circumstances of a catch. Only the first catch
+// of each species is kept.
+type PokedexCapture struct {
+	Dimension string `json:"dimension,omitempty" binding:"max=128"` // e.g. "minecraft:overworld"
+	Biome     string `json:"biome,omitempty" binding:"max=128"`     // e.g. "minecraft:plains"
+	X         *int   `json:"x,omitempty"`
+	Y         *int   `json:"y,omitempty"`
+	Z         *int   `json:"z,omitempty"`
+	Ball      string `json:"ball,omitempty" binding:"max=64"` // e.g. "cobblemon:ultra_ball"
+	Level     int    `json:"level,omitempty" binding:"omitempty,min=1"`
+}
+
+// PokedexEntry is a player's journal entry for one species.
+type PokedexEntry struct {
+	NationalID    int             `json:"national_id" db:"national_id"`
+	Name          string          `json:"name"`
+	Caught        bool            `json:"caught"`
+	Seen          bool            `json:"seen"`
+	OwnedCount    int             `json:"owned_count" db:"owned_count"` // Currently in party + PC
+	FirstSeenAt   *time.Time      `json:"first_seen_at" db:"first_seen_at"`
+	FirstCaughtAt *time.Time      `json:"first_caught_at" db:"first_caught_at"`
+	CaughtForm    string          `json:"caught_form,omitempty" db:"form"` // Form and shininess of the first catch
+	CaughtShiny   bool            `json:"caught_shiny,omitempty" db:"shiny"`
+	Capture       *PokedexCapture `json:"capture,omitempty"`
+}
+
+// PokedexFormEntry is a player's record of an alternate form or shiny.
+type PokedexFormEntry struct {
+	NationalID int       `json:"national_id" db:"national_id"`
+	Name       string    `json:"name"`
+	Form       string    `json:"form" db:"form"`
+	FormName   string    `json:"form_name"`
+	Shiny      bool      `json:"shiny" db:"shiny"`
+	Caught     bool      `json:"caught" db:"caught"`
+	Seen       bool      `json:"seen" db:"seen"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PokedexMilestone records when a player first reached a percentage of a
+// regional Pokédex. Kind is "caught" or "seen"; caught species count as seen.
+type PokedexMilestone struct {
+	ID         int       `json:"id" db:"id"`
+	Region     string    `json:"region" db:"region"`
+	Kind       string    `json:"kind" db:"kind"`
+	Percentage int       `json:"percentage" db:"percentage"`
+	ReachedAt  time.Time `json:"reached_at" db:"reached_at"`
+}
+
+// PokedexBatchResult is the outcome of one batch event, by its position in
+// the request.
+type PokedexBatchResult struct {
+	Index      int    `json:"index"`
+	PlayerUUID string `json:"player_uuid"`
+	NationalID int    `json:"national_id"`
+	Action     string `json:"action"`
+	Status     string `json:"status"` // "applied" or "failed"
+	Error      string `json:"error,omitempty"`
+}
+
+type PokedexBatchResponse struct {
+	Applied int                  `json:"applied"`
+	Failed  int                  `json:"failed"`
+	Results []PokedexBatchResult `json:"results"`
+}
+
+// PokedexOwnership is how many of one species a player currently owns.
+type PokedexOwnership struct {
+	NationalID int `json:"national_id" binding:"required"`
+	Count      int `json:"count" binding:"min=0"`
+}
+
+type LivingDexLeaderboardEntry struct {
+	PlayerID                int     `json:"player_id" db:"player_id"`
+	Username                string  `json:"username" db:"username"`
+	LivingCompletionPercent float64 `json:"living_completion_percentage" db:"living_completion_percentage"`
+	LivingOwned             int     `json:"living_owned" db:"living_owned"`
 }
 
 type LeaderboardEntry struct {
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Server-to-API request models for Minecraft server proxy operations
 type ServerPlayerRequest struct {
 	PlayerUUID string `json:"player_uuid" binding:"required"`
@@ -27,12 +29,51 @@ type ServerPokedexRequest struct {
 }
 
 type ServerPokedexUpdateRequest struct {
+	PlayerUUID string          `json:"player_uuid" binding:"required"`
+	NationalID int             `json:"national_id" binding:"required"`
+	Action     string          `json:"action" binding:"required,oneof=catch see uncatch unsee"`
+	Form       string          `json:"form,omitempty"` // Alternate form ID, e.g. "alola" or "mega-x"
+	Shiny      bool            `json:"shiny,omitempty"`
+	Capture    *PokedexCapture `json:"capture,omitempty"`
+	OccurredAt *time.Time      `json:"occurred_at,omitempty"` // When the mod observed the event, if queued
+}
+
+type ServerPokedexResetRequest struct {
+	PlayerUUID string `json:"player_uuid" binding:"required"`
+	Region     string `json:"region,omitempty"` // Empty resets every region
+}
+
+// ServerPokedexMilestonesRequest pages through a player's milestones; pass
+// the last ID seen as AfterID to get only newer ones.
+type ServerPokedexMilestonesRequest struct {
+	PlayerUUID string `json:"player_uuid" binding:"required"`
+	Region     string `json:"region,omitempty"`
+	AfterID    int    `json:"after_id,omitempty"`
+}
+
+// ServerPokedexOwnershipRequest reports a player's current party + PC counts
+// per species. Without partial it is a full snapshot: species not listed are
+// no longer owned.
+type ServerPokedexOwnershipRequest struct {
+	PlayerUUID string             `json:"player_uuid" binding:"required"`
+	Counts     []PokedexOwnership `json:"counts" binding:"max=2000,dive"`
+	Partial    bool               `json:"partial,omitempty"`
+}
+
+type ServerPokedexEntryRequest struct {
 	PlayerUUID string `json:"player_uuid" binding:"required"`
 	NationalID int    `json:"national_id" binding:"required"`
-	Action     string `json:"action" binding:"required"` // "catch" or "see"
 }
 
+// ServerPokedexBatchRequest carries queued catch/see events for any number of
+// players. Events are validated one by one, so a bad event fails on its own.
+type ServerPokedexBatchRequest struct {
+	Events []ServerPokedexUpdateRequest `json:"events" binding:"required,min=1,max=1000"`
+}
+
+// ServerAuthRequest may omit both fields when the connection carries a
+// verified client certificate identifying the server.
 type ServerAuthRequest struct {
-	ServerID string `json:"server_id" binding:"required"`
-	ServerKey string `json:"server_key" binding:"required"`
-}
\ No newline at end of file
+	ServerID  string `json:"server_id"`
+	ServerKey string `json:"server_key"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type AuthSession struct {
+	ID               int        `json:"id" db:"id"`
+	JTI              string     `json:"-" db:"jti"`
+	SessionType      string     `json:"session_type" db:"session_type"` // "player", "server" or "web"
+	PlayerID         *int       `json:"player_id,omitempty" db:"player_id"`
+	ServerID         *string    `json:"server_id,omitempty" db:"server_id"`
+	Scopes           string     `json:"scopes" db:"scopes"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at" db:"refresh_expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt       time.Time  `json:"last_used_at" db:"last_used_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	Current          bool       `json:"current"`
+}
+
+type TokenRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -29,11 +29,30 @@ type WebServerAnalytics struct {
 }
 
 type WebPokemonPopularity struct {
-	NationalID    int     `json:"national_id"`
-	Name          string  `json:"name,omitempty"`
-	Region        string  `json:"region"`
-	CatchCount    int     `json:"catch_count"`
-	SeenCount     int     `json:"seen_count"`
-	PopularityRank int    `json:"popularity_rank"`
-	CatchRate     float64 `json:"catch_rate_percentage"`
+	NationalID     int     `json:"national_id"`
+	Name           string  `json:"name,omitempty"`
+	Region         string  `json:"region"`
+	CatchCount     int     `json:"catch_count"` // Players who have caught the species
+	SeenCount      int     `json:"seen_count"`
+	PopularityRank int     `json:"popularity_rank"` // 1 is the most caught; ties share a rank
+	CatchRate      float64 `json:"catch_rate_percentage"`
+	RarityTier     string  `json:"rarity_tier"` // See Rarity* constants
+}
+
+// Rarity tiers by the share of players who have caught a species.
+const (
+	RarityCommon   = "common"    // Caught by at least 50% of players
+	RarityUncommon = "uncommon"  // At least 20%
+	RarityRare     = "rare"      // At least 5%
+	RarityVeryRare = "very_rare" // Fewer than 5%, but at least one player
+	RarityUncaught = "uncaught"  // No player yet
+)
+
+// IsKnownRarityTier reports whether tier is a valid rarity tier.
+func IsKnownRarityTier(tier string) bool {
+	switch tier {
+	case RarityCommon, RarityUncommon, RarityRare, RarityVeryRare, RarityUncaught:
+		return true
+	}
+	return false
 }
\ No newline at end of file
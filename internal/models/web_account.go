@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+type WebAccount struct {
+	ID           int        `json:"id" db:"id"`
+	PlayerID     int        `json:"player_id" db:"player_id"`
+	PasswordHash *string    `json:"-" db:"password_hash"` // Nil until the player sets a password
+	LinkedAt     time.Time  `json:"linked_at" db:"linked_at"`
+	LastLoginAt  *time.Time `json:"last_login_at" db:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type WebLinkRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Password string `json:"password" binding:"omitempty,min=8,max=72"` // Optional - enables username/password login
+}
+
+type WebLoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type WebPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"omitempty,max=72"` // Required once the account has a password
+	Password        string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type WebAccountResponse struct {
+	Player      Player     `json:"player"`
+	HasPassword bool       `json:"has_password"`
+	LinkedAt    time.Time  `json:"linked_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// How many Take calls between sweeps of idle buckets.
+const memorySweepInterval = 1000
+
+// MemoryStore keeps buckets in process memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+func (m *MemoryStore) Take(key string, rule Rule, cost float64) (Result, error) {
+	if !rule.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.calls++
+	if m.calls%memorySweepInterval == 0 {
+		m.sweep(now)
+	}
+
+	bucket, exists := m.buckets[key]
+	if !exists {
+		bucket = &memoryBucket{tokens: float64(rule.Burst), updated: now}
+		m.buckets[key] = bucket
+	}
+
+	tokens, result := take(bucket.tokens, now.Sub(bucket.updated), rule, cost)
+	bucket.tokens = tokens
+	bucket.updated = now
+	bucket.period = rule.Period
+
+	return result, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+func (m *MemoryStore) sweep(now time.Time) {
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.updated) > bucket.period {
+			delete(m.buckets, key)
+		}
+	}
+}
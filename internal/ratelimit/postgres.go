@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+)
+
+// How many Take calls between deletes of stale bucket rows.
+const postgresSweepInterval = 1000
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every API
+// instance pointed at the same database shares the same limits. Elapsed time
+// is measured with the database clock to avoid skew between instances.
+type PostgresStore struct {
+	db    *sql.DB
+	calls atomic.Int64
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (p *PostgresStore) Take(key string, rule Rule, cost float64) (Result, error) {
+	if !rule.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+
+	if p.calls.Add(1)%postgresSweepInterval == 0 {
+		p.db.Exec(`DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - INTERVAL '1 day'`)
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (bucket_key) DO NOTHING`, key, rule.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var tokens, elapsedSeconds float64
+	err = tx.QueryRow(`
+		SELECT tokens, EXTRACT(EPOCH FROM NOW() - updated_at)
+		FROM rate_limit_buckets WHERE bucket_key = $1
+		FOR UPDATE`, key).Scan(&tokens, &elapsedSeconds)
+	if err != nil {
+		return Result{}, err
+	}
+
+	elapsed := time.Duration(elapsedSeconds * float64(time.Second))
+	tokens, result := take(tokens, elapsed, rule, cost)
+
+	_, err = tx.Exec(`UPDATE rate_limit_buckets SET tokens = $1, updated_at = NOW() WHERE bucket_key = $2`, tokens, key)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"pokefactory_server/internal/config"
+)
+
+// Rule is a token bucket: up to Burst requests at once, refilled evenly so
+// that Burst more are available after Period. A zero Rule disables limiting.
+type Rule struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled reports whether the rule limits anything.
+func (r Rule) Enabled() bool {
+	return r.Burst > 0 && r.Period > 0
+}
+
+func (r Rule) ratePerSecond() float64 {
+	return float64(r.Burst) / r.Period.Seconds()
+}
+
+// Result is the outcome of taking from a bucket.
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Store keeps bucket state. Take allows the request when the bucket holds at
+// least one token and then removes cost tokens; a cost of 0 only checks.
+type Store interface {
+	Take(key string, rule Rule, cost float64) (Result, error)
+}
+
+// Limits are the configured rules for each protected surface.
+type Limits struct {
+	Login          Rule // Per IP, player login and token refresh
+	ServerAuth     Rule // Per IP, server authentication
+	AuthFailures   Rule // Per server_id and IP, failed server authentications before lockout
+	Web            Rule // Per IP, public web dashboard
+	ServerRequests Rule // Per server_id, authenticated server routes
+	PlayerRequests Rule // Per player, authenticated player routes
+}
+
+// Limiter bundles the store with the configured limits.
+type Limiter struct {
+	Store  Store
+	Limits Limits
+}
+
+// New builds a limiter from configuration. The postgres store shares bucket
+// state between instances; the memory store is per process.
+func New(cfg config.RateLimitConfig, db *sql.DB) (*Limiter, error) {
+	limiter := &Limiter{}
+
+	rules := []struct {
+		value string
+		rule  *Rule
+	}{
+		{cfg.Login, &limiter.Limits.Login},
+		{cfg.ServerAuth, &limiter.Limits.ServerAuth},
+		{cfg.AuthFailures, &limiter.Limits.AuthFailures},
+		{cfg.Web, &limiter.Limits.Web},
+		{cfg.ServerRequests, &limiter.Limits.ServerRequests},
+		{cfg.PlayerRequests, &limiter.Limits.PlayerRequests},
+	}
+	for _, r := range rules {
+		rule, err := ParseRule(r.value)
+		if err != nil {
+			return nil, err
+		}
+		*r.rule = rule
+	}
+
+	switch cfg.Store {
+	case "", "memory":
+		limiter.Store = NewMemoryStore()
+	case "postgres":
+		limiter.Store = NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %s", cfg.Store)
+	}
+
+	return limiter, nil
+}
+
+// ParseRule parses "<burst>/<period>", e.g. "10/1m" or "600/1h". An empty
+// value, "0" or "off" disables the rule.
+func ParseRule(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || value == "off" {
+		return Rule{}, nil
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: expected <burst>/<period>", value)
+	}
+
+	burst, err := strconv.Atoi(parts[0])
+	if err != nil || burst < 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit burst in %q", value)
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit period in %q", value)
+	}
+
+	return Rule{Burst: burst, Period: period}, nil
+}
+
+// take applies the token bucket algorithm to a bucket that held tokens
+// elapsed ago and returns the new token count.
+func take(tokens float64, elapsed time.Duration, rule Rule, cost float64) (float64, Result) {
+	rate := rule.ratePerSecond()
+	tokens = math.Min(float64(rule.Burst), tokens+elapsed.Seconds()*rate)
+
+	if tokens < 1 {
+		wait := time.Duration((1 - tokens) / rate * float64(time.Second))
+		return tokens, Result{Allowed: false, RetryAfter: wait}
+	}
+
+	return math.Max(0, tokens-cost), Result{Allowed: true}
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Rule
+		wantErr bool
+	}{
+		{value: "10/1m", want: Rule{Burst: 10, Period: time.Minute}},
+		{value: " 600/1h ", want: Rule{Burst: 600, Period: time.Hour}},
+		{value: "5/15m", want: Rule{Burst: 5, Period: 15 * time.Minute}},
+		{value: "", want: Rule{}},
+		{value: "0", want: Rule{}},
+		{value: "off", want: Rule{}},
+		{value: "10", wantErr: true},
+		{value: "10/", wantErr: true},
+		{value: "/1m", wantErr: true},
+		{value: "ten/1m", wantErr: true},
+		{value: "-1/1m", wantErr: true},
+		{value: "10/1 minute", wantErr: true},
+		{value: "10/0s", wantErr: true},
+		{value: "10/-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRule(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRule(%q) = %+v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRule(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	rule := Rule{Burst: 3, Period: time.Minute} // One token every 20s
+
+	tests := []struct {
+		name    string
+		idle    time.Duration // How long the bucket sits before the final take
+		takes   int           // Takes before the idle period
+		allowed bool
+	}{
+		{name: "within burst", takes: 2, allowed: true},
+		{name: "burst exhausted", takes: 3, allowed: false},
+		{name: "partial refill", takes: 3, idle: 10 * time.Second, allowed: false},
+		{name: "one token refilled", takes: 3, idle: 21 * time.Second, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			for i := 0; i < tt.takes; i++ {
+				if result, _ := store.Take("ip:1", rule, 1); !result.Allowed {
+					t.Fatalf("take %d denied within burst", i+1)
+				}
+			}
+			if bucket, exists := store.buckets["ip:1"]; exists {
+				bucket.updated = bucket.updated.Add(-tt.idle)
+			}
+
+			result, err := store.Take("ip:1", rule, 1)
+			if err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if result.Allowed != tt.allowed {
+				t.Errorf("Allowed = %t, want %t", result.Allowed, tt.allowed)
+			}
+			if !result.Allowed && (result.RetryAfter <= 0 || result.RetryAfter > 20*time.Second) {
+				t.Errorf("RetryAfter = %s, want within one refill interval", result.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRefillCappedAtBurst(t *testing.T) {
+	store := NewMemoryStore()
+	rule := Rule{Burst: 2, Period: time.Minute}
+
+	store.Take("ip:1", rule, 1)
+	store.buckets["ip:1"].updated = time.Now().Add(-time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if result, _ := store.Take("ip:1", rule, 1); !result.Allowed {
+			t.Fatalf("take %d denied after refill", i+1)
+		}
+	}
+	if result, _ := store.Take("ip:1", rule, 1); result.Allowed {
+		t.Error("bucket refilled beyond its burst")
+	}
+}
+
+func TestMemoryStoreKeysAndCost(t *testing.T) {
+	store := NewMemoryStore()
+	rule := Rule{Burst: 1, Period: time.Minute}
+
+	store.Take("ip:1", rule, 1)
+	if result, _ := store.Take("ip:1", rule, 1); result.Allowed {
+		t.Error("ip:1 allowed past its burst")
+	}
+	if result, _ := store.Take("ip:2", rule, 1); !result.Allowed {
+		t.Error("ip:2 limited by ip:1's bucket")
+	}
+
+	// A zero cost only checks the bucket
+	for i := 0; i < 3; i++ {
+		if result, _ := store.Take("ip:3", rule, 0); !result.Allowed {
+			t.Fatalf("check %d denied", i+1)
+		}
+	}
+
+	// A disabled rule never limits
+	for i := 0; i < 3; i++ {
+		if result, _ := store.Take("ip:4", Rule{}, 1); !result.Allowed {
+			t.Fatalf("take %d denied by a disabled rule", i+1)
+		}
+	}
+}
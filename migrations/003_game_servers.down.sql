@@ -0,0 +1,2 @@
+-- Drop game servers table
+DROP TABLE IF EXISTS game_servers;
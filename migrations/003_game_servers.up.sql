@@ -0,0 +1,13 @@
+-- Create game_servers table for per-server credentials
+CREATE TABLE IF NOT EXISTS game_servers (
+    id SERIAL PRIMARY KEY,
+    server_id VARCHAR(64) UNIQUE NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    last_auth_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_game_servers_server_id ON game_servers(server_id);
@@ -0,0 +1,2 @@
+-- Drop login tickets table
+DROP TABLE IF EXISTS login_tickets;
@@ -0,0 +1,13 @@
+-- Create login_tickets table for server-attested player logins
+CREATE TABLE IF NOT EXISTS login_tickets (
+    id SERIAL PRIMARY KEY,
+    ticket_hash VARCHAR(64) UNIQUE NOT NULL,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    server_id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_login_tickets_expires_at ON login_tickets(expires_at);
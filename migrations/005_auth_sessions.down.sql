@@ -0,0 +1,2 @@
+-- Drop auth sessions table
+DROP TABLE IF EXISTS auth_sessions;
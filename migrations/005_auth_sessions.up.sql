@@ -0,0 +1,17 @@
+-- Create auth_sessions table backing refresh tokens and token revocation
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id SERIAL PRIMARY KEY,
+    jti VARCHAR(64) UNIQUE NOT NULL,
+    session_type VARCHAR(16) NOT NULL,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    server_id VARCHAR(64),
+    refresh_token_hash VARCHAR(64) UNIQUE NOT NULL,
+    refresh_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_player_id ON auth_sessions(player_id);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_server_id ON auth_sessions(server_id);
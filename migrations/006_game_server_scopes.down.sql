@@ -0,0 +1,3 @@
+-- Remove game server scopes
+ALTER TABLE game_servers DROP COLUMN IF EXISTS scopes;
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS scopes;
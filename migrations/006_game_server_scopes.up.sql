@@ -0,0 +1,6 @@
+-- Add scopes granted to each game server's tokens (space-separated)
+ALTER TABLE game_servers
+    ADD COLUMN IF NOT EXISTS scopes TEXT NOT NULL DEFAULT 'player:read player:write pokedex:read pokedex:write';
+
+-- Record the scopes granted to each session so refreshed tokens keep them
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS scopes TEXT NOT NULL DEFAULT '';
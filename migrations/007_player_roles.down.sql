@@ -0,0 +1,3 @@
+-- Remove player roles
+DROP INDEX IF EXISTS idx_players_username;
+ALTER TABLE players DROP COLUMN IF EXISTS role;
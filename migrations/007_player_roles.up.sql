@@ -0,0 +1,5 @@
+-- Add player roles ("player" or "admin")
+ALTER TABLE players ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'player';
+
+-- Create indexes for admin player search
+CREATE INDEX IF NOT EXISTS idx_players_username ON players(LOWER(username));
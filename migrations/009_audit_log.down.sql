@@ -0,0 +1,2 @@
+-- Drop audit log table
+DROP TABLE IF EXISTS audit_log;
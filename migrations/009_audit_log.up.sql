@@ -0,0 +1,16 @@
+-- Create audit_log table recording every write operation
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_type VARCHAR(16) NOT NULL,
+    actor_id VARCHAR(64) NOT NULL,
+    player_id INTEGER REFERENCES players(id) ON DELETE SET NULL,
+    action VARCHAR(64) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    before_value JSONB,
+    after_value JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_audit_log_player_id ON audit_log(player_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
@@ -0,0 +1,3 @@
+-- Drop request signing
+DROP TABLE IF EXISTS request_nonces;
+ALTER TABLE game_servers DROP COLUMN IF EXISTS require_signing;
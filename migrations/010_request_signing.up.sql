@@ -0,0 +1,13 @@
+-- Allow individual servers to be required to sign every request
+ALTER TABLE game_servers ADD COLUMN IF NOT EXISTS require_signing BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create request_nonces table for replay protection of signed requests
+CREATE TABLE IF NOT EXISTS request_nonces (
+    server_id VARCHAR(64) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (server_id, nonce)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_request_nonces_expires_at ON request_nonces(expires_at);
@@ -0,0 +1,3 @@
+-- Drop web account tables
+DROP TABLE IF EXISTS web_accounts;
+DROP TABLE IF EXISTS web_link_codes;
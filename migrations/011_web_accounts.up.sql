@@ -0,0 +1,25 @@
+-- Create web_link_codes table for in-game web account linking
+CREATE TABLE IF NOT EXISTS web_link_codes (
+    id SERIAL PRIMARY KEY,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    server_id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create web_accounts table binding web dashboard logins to players
+CREATE TABLE IF NOT EXISTS web_accounts (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER UNIQUE NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255),
+    linked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_web_link_codes_player_id ON web_link_codes(player_id);
+CREATE INDEX IF NOT EXISTS idx_web_link_codes_expires_at ON web_link_codes(expires_at);
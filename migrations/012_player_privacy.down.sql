@@ -0,0 +1,3 @@
+-- Remove player privacy setting
+DROP INDEX IF EXISTS idx_players_privacy;
+ALTER TABLE players DROP COLUMN IF EXISTS privacy;
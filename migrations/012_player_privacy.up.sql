@@ -0,0 +1,5 @@
+-- Add privacy setting to players: public, unlisted or private
+ALTER TABLE players ADD COLUMN IF NOT EXISTS privacy VARCHAR(16) NOT NULL DEFAULT 'public';
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_players_privacy ON players(privacy);
@@ -0,0 +1,7 @@
+-- Drop form and shiny tracking
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS shiny_completion_percentage;
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS shiny_caught;
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS forms_completion_percentage;
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS forms_caught;
+
+DROP TABLE IF EXISTS player_pokedex_forms;
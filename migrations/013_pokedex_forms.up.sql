@@ -0,0 +1,23 @@
+-- Create player_pokedex_forms table for alternate form and shiny entries.
+-- Default, non-shiny catches are covered by the regional Pokédex flags.
+CREATE TABLE IF NOT EXISTS player_pokedex_forms (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    national_id INTEGER NOT NULL,
+    form VARCHAR(32) NOT NULL DEFAULT '',
+    shiny BOOLEAN NOT NULL DEFAULT FALSE,
+    caught BOOLEAN NOT NULL DEFAULT FALSE,
+    seen BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id, national_id, form, shiny)
+);
+
+-- Add forms dex and shiny dex completion to the summary
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS forms_caught INTEGER DEFAULT 0;
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS forms_completion_percentage DECIMAL(5,2) DEFAULT 0.00;
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS shiny_caught INTEGER DEFAULT 0;
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS shiny_completion_percentage DECIMAL(5,2) DEFAULT 0.00;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_forms_species ON player_pokedex_forms(national_id, form);
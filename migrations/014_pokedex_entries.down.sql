@@ -0,0 +1,2 @@
+-- Drop per-entry Pokédex metadata
+DROP TABLE IF EXISTS player_pokedex_entries;
@@ -0,0 +1,26 @@
+-- Create player_pokedex_entries table: one row per player and species with
+-- first seen/caught times and where and how the first catch happened
+CREATE TABLE IF NOT EXISTS player_pokedex_entries (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    national_id INTEGER NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE,
+    first_caught_at TIMESTAMP WITH TIME ZONE,
+    dimension VARCHAR(128),
+    biome VARCHAR(128),
+    x INTEGER,
+    y INTEGER,
+    z INTEGER,
+    ball VARCHAR(64),
+    level INTEGER,
+    form VARCHAR(32),
+    shiny BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id, national_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_entries_first_caught ON player_pokedex_entries(player_id, first_caught_at DESC);
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_entries_species ON player_pokedex_entries(national_id);
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_entries_biome ON player_pokedex_entries(biome);
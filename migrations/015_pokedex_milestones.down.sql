@@ -0,0 +1,2 @@
+-- Drop Pokédex milestone history
+DROP TABLE IF EXISTS player_pokedex_milestones;
@@ -0,0 +1,14 @@
+-- Create player_pokedex_milestones table: the first time a player reached each
+-- configured caught/seen percentage of a regional Pokédex
+CREATE TABLE IF NOT EXISTS player_pokedex_milestones (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    region VARCHAR(32) NOT NULL,
+    kind VARCHAR(16) NOT NULL, -- 'caught' or 'seen'
+    percentage INTEGER NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id, region, kind, percentage)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_milestones_player ON player_pokedex_milestones(player_id, id);
@@ -0,0 +1,173 @@
+-- Drop unified Pokédex storage, rebuilding the per-region bitfield tables
+
+-- Recreate the per-region tables
+CREATE TABLE IF NOT EXISTS player_pokedex_kanto (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x0000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x0000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_johto (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x0000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x0000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_hoenn (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_sinnoh (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x0000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x0000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_unova (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x000000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x000000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_kalos (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_alola (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_galar (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x000000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x000000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_hisui (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x0000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x0000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_pokedex_paldea (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER REFERENCES players(id) ON DELETE CASCADE,
+    caught_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000000000',
+    seen_flags BYTEA DEFAULT '\x00000000000000000000000000000000000000000000',
+    completion_date TIMESTAMP WITH TIME ZONE,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id)
+);
+
+-- Rebuild each region's bitfields from the entries, regional number n + 1
+-- being bit n of the region's national range
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT * FROM (VALUES
+        ('kanto', 1, 151), ('johto', 152, 251), ('hoenn', 252, 386), ('sinnoh', 387, 493),
+        ('unova', 494, 649), ('kalos', 650, 721), ('alola', 722, 809), ('galar', 810, 898),
+        ('hisui', 899, 905), ('paldea', 906, 1025)
+    ) AS regions(name, first_id, last_id)
+    LOOP
+        EXECUTE format($sql$
+            INSERT INTO %1$I (player_id, caught_flags, seen_flags, completion_date, completion_percentage, created_at, updated_at)
+            SELECT pr.player_id,
+                   (SELECT decode(string_agg(lpad(to_hex(COALESCE((
+                        SELECT SUM(1 << ((e.national_id - %3$s) %% 8))
+                        FROM player_pokedex_entries e
+                        WHERE e.player_id = pr.player_id AND e.caught
+                          AND e.national_id BETWEEN %3$s + b * 8 AND LEAST(%3$s + b * 8 + 7, %4$s)
+                    ), 0)::int), 2, '0'), '' ORDER BY b), 'hex') FROM generate_series(0, (%4$s - %3$s) / 8) AS b),
+                   (SELECT decode(string_agg(lpad(to_hex(COALESCE((
+                        SELECT SUM(1 << ((e.national_id - %3$s) %% 8))
+                        FROM player_pokedex_entries e
+                        WHERE e.player_id = pr.player_id AND e.seen
+                          AND e.national_id BETWEEN %3$s + b * 8 AND LEAST(%3$s + b * 8 + 7, %4$s)
+                    ), 0)::int), 2, '0'), '' ORDER BY b), 'hex') FROM generate_series(0, (%4$s - %3$s) / 8) AS b),
+                   pr.completion_date, pr.completion_percentage, pr.created_at, pr.updated_at
+            FROM player_pokedex_regions pr
+            WHERE pr.region = %2$L
+        $sql$, 'player_pokedex_' || r.name, r.name, r.first_id, r.last_id);
+    END LOOP;
+END $$;
+
+DROP TABLE IF EXISTS player_pokedex_regions;
+DROP INDEX IF EXISTS idx_player_pokedex_entries_caught;
+ALTER TABLE player_pokedex_entries DROP COLUMN IF EXISTS caught;
+ALTER TABLE player_pokedex_entries DROP COLUMN IF EXISTS seen;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_pokedex_kanto_player_id ON player_pokedex_kanto(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_johto_player_id ON player_pokedex_johto(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_hoenn_player_id ON player_pokedex_hoenn(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_sinnoh_player_id ON player_pokedex_sinnoh(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_unova_player_id ON player_pokedex_unova(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_kalos_player_id ON player_pokedex_kalos(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_alola_player_id ON player_pokedex_alola(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_galar_player_id ON player_pokedex_galar(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_hisui_player_id ON player_pokedex_hisui(player_id);
+CREATE INDEX IF NOT EXISTS idx_pokedex_paldea_player_id ON player_pokedex_paldea(player_id);
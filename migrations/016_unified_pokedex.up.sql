@@ -0,0 +1,73 @@
+-- Replace the ten per-region bitfield tables with national-keyed storage.
+-- Caught and seen flags move onto player_pokedex_entries (one row per player
+-- and species); regional numbering now comes from the species catalog, so
+-- only each region's completion is stored, in player_pokedex_regions.
+
+-- Add caught/seen flags to the per-species entries
+ALTER TABLE player_pokedex_entries ADD COLUMN IF NOT EXISTS caught BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE player_pokedex_entries ADD COLUMN IF NOT EXISTS seen BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create player_pokedex_regions table for regional completion
+CREATE TABLE IF NOT EXISTS player_pokedex_regions (
+    id SERIAL PRIMARY KEY,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    region VARCHAR(32) NOT NULL,
+    completion_percentage DECIMAL(5,2) DEFAULT 0.00,
+    completion_date TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id, region)
+);
+
+-- Move the data. Bit n (least significant bit first within each byte, as
+-- get_bit counts) of a region's flags is regional number n + 1, which these
+-- tables numbered from the start of the region's national range.
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT * FROM (VALUES
+        ('kanto', 1, 151), ('johto', 152, 251), ('hoenn', 252, 386), ('sinnoh', 387, 493),
+        ('unova', 494, 649), ('kalos', 650, 721), ('alola', 722, 809), ('galar', 810, 898),
+        ('hisui', 899, 905), ('paldea', 906, 1025)
+    ) AS regions(name, first_id, last_id)
+    LOOP
+        EXECUTE format($sql$
+            INSERT INTO player_pokedex_entries AS e (player_id, national_id, caught, seen, created_at, updated_at)
+            SELECT p.player_id, %2$s + bit.n, f.caught, f.seen, p.created_at, p.updated_at
+            FROM %1$I p
+            CROSS JOIN generate_series(0, %3$s - %2$s) AS bit(n)
+            CROSS JOIN LATERAL (SELECT
+                CASE WHEN bit.n < length(p.caught_flags) * 8 THEN get_bit(p.caught_flags, bit.n) = 1 ELSE FALSE END AS caught,
+                CASE WHEN bit.n < length(p.seen_flags) * 8 THEN get_bit(p.seen_flags, bit.n) = 1 ELSE FALSE END AS seen
+            ) AS f
+            WHERE p.player_id IS NOT NULL AND (f.caught OR f.seen)
+            ON CONFLICT (player_id, national_id) DO UPDATE
+            SET caught = e.caught OR EXCLUDED.caught, seen = e.seen OR EXCLUDED.seen
+        $sql$, 'player_pokedex_' || r.name, r.first_id, r.last_id);
+
+        EXECUTE format($sql$
+            INSERT INTO player_pokedex_regions (player_id, region, completion_percentage, completion_date, created_at, updated_at)
+            SELECT player_id, %2$L, completion_percentage, completion_date, created_at, updated_at
+            FROM %1$I
+            WHERE player_id IS NOT NULL
+            ON CONFLICT (player_id, region) DO NOTHING
+        $sql$, 'player_pokedex_' || r.name, r.name);
+    END LOOP;
+END $$;
+
+-- Drop the per-region tables
+DROP TABLE IF EXISTS player_pokedex_kanto;
+DROP TABLE IF EXISTS player_pokedex_johto;
+DROP TABLE IF EXISTS player_pokedex_hoenn;
+DROP TABLE IF EXISTS player_pokedex_sinnoh;
+DROP TABLE IF EXISTS player_pokedex_unova;
+DROP TABLE IF EXISTS player_pokedex_kalos;
+DROP TABLE IF EXISTS player_pokedex_alola;
+DROP TABLE IF EXISTS player_pokedex_galar;
+DROP TABLE IF EXISTS player_pokedex_hisui;
+DROP TABLE IF EXISTS player_pokedex_paldea;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_entries_caught ON player_pokedex_entries(national_id) WHERE caught;
+CREATE INDEX IF NOT EXISTS idx_player_pokedex_regions_region ON player_pokedex_regions(region, completion_percentage DESC);
@@ -0,0 +1,6 @@
+-- Drop living dex tracking
+DROP INDEX IF EXISTS idx_pokedex_summary_living_completion;
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS living_completion_percentage;
+ALTER TABLE player_pokedex_summary DROP COLUMN IF EXISTS living_owned;
+ALTER TABLE player_pokedex_entries DROP COLUMN IF EXISTS owned_updated_at;
+ALTER TABLE player_pokedex_entries DROP COLUMN IF EXISTS owned_count;
@@ -0,0 +1,10 @@
+-- Add current ownership (party + PC) per species for the living dex
+ALTER TABLE player_pokedex_entries ADD COLUMN IF NOT EXISTS owned_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE player_pokedex_entries ADD COLUMN IF NOT EXISTS owned_updated_at TIMESTAMP WITH TIME ZONE;
+
+-- Add living dex completion to the summary
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS living_owned INTEGER DEFAULT 0;
+ALTER TABLE player_pokedex_summary ADD COLUMN IF NOT EXISTS living_completion_percentage DECIMAL(5,2) DEFAULT 0.00;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_pokedex_summary_living_completion ON player_pokedex_summary(living_completion_percentage DESC);
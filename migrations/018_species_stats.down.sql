@@ -0,0 +1,2 @@
+-- Drop species popularity aggregate
+DROP TABLE IF EXISTS species_stats;
@@ -0,0 +1,20 @@
+-- Create species_stats table: how many players have caught and seen each
+-- species (caught counting as seen), kept up to date with every Pokédex change
+CREATE TABLE IF NOT EXISTS species_stats (
+    national_id INTEGER PRIMARY KEY,
+    catch_count INTEGER NOT NULL DEFAULT 0,
+    seen_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Fill it from the existing entries
+INSERT INTO species_stats (national_id, catch_count, seen_count, updated_at)
+SELECT national_id, COUNT(*) FILTER (WHERE caught), COUNT(*) FILTER (WHERE caught OR seen), NOW()
+FROM player_pokedex_entries
+WHERE caught OR seen
+GROUP BY national_id
+ON CONFLICT (national_id) DO UPDATE
+SET catch_count = EXCLUDED.catch_count, seen_count = EXCLUDED.seen_count, updated_at = NOW();
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_species_stats_catch_count ON species_stats(catch_count DESC);
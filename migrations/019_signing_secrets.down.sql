@@ -0,0 +1,2 @@
+-- Drop per-server signing secrets
+ALTER TABLE game_servers DROP COLUMN IF EXISTS signing_secret_encrypted;
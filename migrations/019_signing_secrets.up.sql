@@ -0,0 +1,2 @@
+-- Give each server its own request signing secret, sealed with REQUEST_SIGNING_SECRET_KEY
+ALTER TABLE game_servers ADD COLUMN IF NOT EXISTS signing_secret_encrypted TEXT;
@@ -0,0 +1,2 @@
+-- Drop catalog version history
+DROP TABLE IF EXISTS catalog_versions;
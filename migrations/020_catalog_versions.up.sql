@@ -0,0 +1,6 @@
+-- Record each species catalog version whose regions have been applied to
+-- every player's stored completion
+CREATE TABLE IF NOT EXISTS catalog_versions (
+    version VARCHAR(32) PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -10,11 +10,12 @@ echo "=== PokéFactory API Tests ==="
 echo "1. Testing health endpoint..."
 curl -s $API_URL/health | jq '.'
 
-# 2. Server Authentication (change server_id to match your docker-compose yml file)
+# 2. Server Authentication (register first: ./gameserver add test_server)
 echo -e "\n2. Getting server token..."
+SERVER_KEY=${SERVER_KEY:-"your-server-key"}
 SERVER_TOKEN=$(curl -s -X POST $API_URL/api/v1/server/auth \
   -H "Content-Type: application/json" \
-  -d '{"server_id":"test_server","server_key":"your-jwt-secret-key"}' | jq -r '.token')
+  -d "{\"server_id\":\"test_server\",\"server_key\":\"$SERVER_KEY\"}" | jq -r '.token')
 
 echo "Server token: ${SERVER_TOKEN:0:20}..."
 
@@ -35,7 +35,7 @@ public class MinecraftClientTest {
     }
     
     private static void authenticateServer() throws Exception {
-        String json = "{\"server_id\":\"test-server-1\",\"server_key\":\"your-jwt-secret\"}";
+        String json = "{\"server_id\":\"test-server-1\",\"server_key\":\"your-server-key\"}";
         
         HttpRequest request = HttpRequest.newBuilder()
             .uri(URI.create(API_URL + "/api/v1/server/auth"))